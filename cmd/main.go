@@ -1,34 +1,301 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/grpcclient"
 	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/infra/metrics"
+	"go-expert-stress-test/infra/observer"
+	"go-expert-stress-test/infra/rawsocket"
+	"go-expert-stress-test/infra/sse"
+	"go-expert-stress-test/infra/websocket"
 	"go-expert-stress-test/interfaces/cli"
 	"go-expert-stress-test/usecases"
 	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func main() {
 	config := domain.TestConfig{}
+	var metricsAddr, eventsFile, tlsCiphers, oauth2Scopes, bodyFile, messagesFile string
+	var messages []string
+	var graphQLQueryFile, graphQLVariables string
+	var payload, payloadFile, delimiter string
+	var responseBytes int
+	var grpcOptions grpcclient.Options
+	clientOptions := httpclient.DefaultOptions(0)
+	var dashboard, noColor, quiet, sseMode bool
+	var search domain.SearchConfig
+	sseOptions := sse.DefaultOptions()
 
 	// attribui os argumentos ao config
 	flag.StringVar(&config.URL, "url", "", "URL do serviço a ser testado")
 	flag.IntVar(&config.Requests, "requests", 0, "Número total de requests")
 	flag.IntVar(&config.Concurrency, "concurrency", 1, "Número de chamadas simultâneas")
+	flag.IntVar(&config.Rate, "rate", 0, "Limite de requests por segundo (0 = sem limite)")
+	flag.IntVar(&config.Retry.MaxRetries, "retries", 0, "Novas tentativas de cada request que falhar (0 = sem retries)")
+	flag.Var(&retryOnFlag{&config.Retry}, "retry-on", "Status e classes de erro repetidos, separados por vírgula (padrão: 502,503,504,timeout,connection)")
+	flag.DurationVar(&config.Retry.BaseDelay, "retry-backoff", 100*time.Millisecond, "Espera antes do primeiro retry, dobrada a cada nova tentativa")
+	flag.DurationVar(&config.Retry.MaxDelay, "retry-max-backoff", 5*time.Second, "Espera máxima entre tentativas")
+	flag.Var(&thinkTimeFlag{&config.ThinkTime}, "think-time", "Pausa de cada worker entre requests: constant:1s, uniform:1s:3s, normal:2s:500ms ou exponential:2s")
+	flag.DurationVar(&config.Pacing, "pacing", 0, "Intervalo mínimo entre o início de requests do mesmo worker (0 = sem pacing)")
+	flag.StringVar(&search.Mode, "find-max", "", "Busca a vazão máxima aumentando concurrency ou rate a cada degrau de --requests requests")
+	flag.IntVar(&search.Start, "search-start", 10, "Nível do primeiro degrau da busca")
+	flag.IntVar(&search.Step, "search-step", 10, "Incremento do nível entre degraus da busca")
+	flag.IntVar(&search.Max, "search-max", 1000, "Maior nível testado pela busca")
+	flag.DurationVar(&search.MaxP99, "max-p99", 0, "p99 máximo sustentável na busca (0 = ignora)")
+	flag.Float64Var(&search.MaxErrorRate, "max-error-rate", 1, "Percentual máximo (0-100) de requests sem sucesso na busca, ex: 1 = 1%")
+	flag.DurationVar(&clientOptions.Timeout, "timeout", clientOptions.Timeout, "Tempo máximo de cada request (0 = sem limite)")
+	flag.DurationVar(&clientOptions.ConnectTimeout, "connect-timeout", clientOptions.ConnectTimeout, "Tempo máximo para abrir a conexão TCP")
+	flag.DurationVar(&clientOptions.TLSHandshakeTimeout, "tls-handshake-timeout", clientOptions.TLSHandshakeTimeout, "Tempo máximo do handshake TLS")
+	flag.DurationVar(&clientOptions.ResponseHeaderTimeout, "response-header-timeout", 0, "Tempo máximo até receber os cabeçalhos da resposta (0 = sem limite)")
+	flag.IntVar(&clientOptions.MaxIdleConns, "max-idle-conns", 0, "Conexões ociosas mantidas no total (0 = igual à concorrência)")
+	flag.IntVar(&clientOptions.MaxIdleConnsPerHost, "max-idle-conns-per-host", 0, "Conexões ociosas mantidas por host (0 = igual à concorrência)")
+	flag.BoolVar(&clientOptions.DisableKeepAlives, "disable-keep-alive", false, "Abre uma nova conexão para cada request")
+	flag.BoolVar(&clientOptions.HTTP2, "http2", false, "Negocia HTTP/2 via ALPN em alvos HTTPS")
+	flag.BoolVar(&clientOptions.H2C, "h2c", false, "Usa HTTP/2 sem TLS com prior knowledge (h2c)")
+	flag.StringVar(&clientOptions.TLS.CertFile, "cert", "", "Certificado do cliente (PEM) para mTLS")
+	flag.StringVar(&clientOptions.TLS.KeyFile, "key", "", "Chave privada do certificado do cliente (PEM)")
+	flag.StringVar(&clientOptions.TLS.CAFile, "cacert", "", "Bundle de CAs (PEM) usado para validar o servidor")
+	flag.StringVar(&clientOptions.TLS.ServerName, "sni", "", "Sobrescreve o SNI e o nome validado no certificado")
+	flag.StringVar(&clientOptions.TLS.MinVersion, "tls-min-version", "", "Versão mínima de TLS: 1.0, 1.1, 1.2 ou 1.3")
+	flag.StringVar(&tlsCiphers, "tls-ciphers", "", "Cipher suites permitidas em TLS 1.0-1.2, separadas por vírgula")
+	flag.BoolVar(&clientOptions.TLS.Insecure, "insecure", false, "Não valida o certificado do servidor")
+	flag.StringVar(&clientOptions.Proxy, "proxy", "", "Proxy http://, https:// ou socks5:// (padrão: HTTPS_PROXY/HTTP_PROXY)")
+	flag.StringVar(&clientOptions.ProxyUser, "proxy-user", "", "Credenciais do proxy no formato usuario:senha")
+	flag.Var(&listFlag{&clientOptions.Resolve}, "resolve", "Override de DNS no formato host:port:ip, pode ser repetido")
+	flag.BoolVar(&clientOptions.SpreadIPs, "spread-ips", false, "Distribui as conexões entre todos os endereços A/AAAA do host")
+	flag.BoolVar(&clientOptions.PoolPerWorker, "pool-per-worker", false, "Cada worker usa um pool de conexões próprio")
+	flag.StringVar(&clientOptions.Method, "method", "GET", "Método HTTP das requests")
+	flag.StringVar(&bodyFile, "body-file", "", "Arquivo com o corpo enviado em cada request")
+	flag.StringVar(&clientOptions.BodyEncoding, "body-encoding", "", "Comprime o corpo da request com gzip, br ou zstd")
+	flag.StringVar(&clientOptions.AcceptEncoding, "accept-encoding", "gzip", "Valor de Accept-Encoding enviado, ou none para não enviar")
+	flag.StringVar(&graphQLQueryFile, "graphql-query", "", "Arquivo com a query GraphQL enviada em cada request")
+	flag.StringVar(&graphQLVariables, "graphql-variables", "", "Variáveis da query GraphQL em JSON")
+	flag.StringVar(&clientOptions.GraphQL.OperationName, "graphql-operation", "", "Operação GraphQL executada quando a query define mais de uma")
+	flag.Var(&redirectsFlag{&clientOptions.MaxRedirects}, "follow-redirects", "Número máximo de redirects seguidos por request, ou none (padrão: 10)")
+	flag.StringVar(&clientOptions.Auth.Basic, "basic-auth", "", "Autenticação basic no formato usuario:senha")
+	flag.StringVar(&clientOptions.Auth.Bearer, "bearer", "", "Token bearer estático")
+	flag.StringVar(&clientOptions.Auth.OAuth2.TokenURL, "oauth2-token-url", "", "Token endpoint do fluxo OAuth2 client credentials")
+	flag.StringVar(&clientOptions.Auth.OAuth2.ClientID, "oauth2-client-id", "", "Client ID OAuth2")
+	flag.StringVar(&clientOptions.Auth.OAuth2.ClientSecret, "oauth2-client-secret", "", "Client secret OAuth2")
+	flag.StringVar(&oauth2Scopes, "oauth2-scopes", "", "Scopes OAuth2 separados por vírgula")
+	flag.Var(&listFlag{&messages}, "message", "Mensagem enviada em alvos ws:// e wss://, pode ser repetida para formar um roteiro")
+	flag.StringVar(&messagesFile, "messages-file", "", "Arquivo com o roteiro de mensagens WebSocket, uma por linha")
+	flag.StringVar(&grpcOptions.Method, "grpc-method", "", "Método gRPC no formato pacote.Servico/Metodo, para alvos grpc:// e grpcs://")
+	flag.StringVar(&grpcOptions.Request, "grpc-request", "", "Mensagem de requisição gRPC em JSON")
+	flag.StringVar(&grpcOptions.DescriptorSet, "proto-set", "", "Arquivo FileDescriptorSet do serviço gRPC (padrão: reflection do servidor)")
+	flag.StringVar(&payload, "payload", "", "Payload enviado em alvos tcp:// e udp://, aceita escapes como \\n")
+	flag.StringVar(&payloadFile, "payload-file", "", "Arquivo com o payload enviado em alvos tcp:// e udp://")
+	flag.StringVar(&delimiter, "delimiter", "", "Delimitador que encerra a resposta TCP/UDP, aceita escapes como \\n")
+	flag.IntVar(&responseBytes, "response-bytes", 0, "Número de bytes que encerra a resposta TCP/UDP")
+	flag.BoolVar(&sseMode, "sse", false, "Mantém cada request aberta como um stream Server-Sent Events")
+	flag.DurationVar(&sseOptions.Hold, "sse-hold", sseOptions.Hold, "Tempo que cada stream SSE fica aberto")
+	flag.IntVar(&sseOptions.MaxEvents, "sse-events", 0, "Encerra cada stream SSE após este número de eventos (0 = sem limite)")
+	flag.BoolVar(&dashboard, "dashboard", false, "Exibe um painel agregado com métricas móveis no lugar das barras por worker")
+	flag.BoolVar(&noColor, "no-color", false, "Desabilita as cores na saída")
+	flag.BoolVar(&quiet, "quiet", false, "Não exibe progresso nem banner, apenas o resumo final")
+	flag.StringVar(&metricsAddr, "metrics-addr", "", "Endereço para expor métricas Prometheus em /metrics (ex: :9090)")
+	flag.StringVar(&eventsFile, "events", "", "Arquivo onde os eventos do teste são gravados em JSON, um por linha")
 	flag.Parse()
 
 	// minima validação
 	if config.URL == "" || config.Requests <= 0 {
 		log.Fatal("URL e número de requests são obrigatórios")
 	}
+	if config.Concurrency <= 0 {
+		log.Fatal("--concurrency deve ser maior que zero")
+	}
+	if config.Retry.MaxRetries < 0 {
+		log.Fatal("--retries não pode ser negativo")
+	}
+	if config.Pacing < 0 {
+		log.Fatal("--pacing não pode ser negativo")
+	}
+	if search.Mode != "" {
+		if err := search.Validate(); err != nil {
+			log.Fatalf("Parâmetros de busca inválidos: %v", err)
+		}
+	}
+	if clientOptions.HTTP2 && clientOptions.H2C {
+		log.Fatal("--http2 e --h2c não podem ser usados juntos")
+	}
+
+	if bodyFile != "" {
+		body, err := os.ReadFile(bodyFile)
+		if err != nil {
+			log.Fatalf("Erro ao ler o corpo da request: %v", err)
+		}
+		clientOptions.Body = body
+	}
+	if graphQLQueryFile != "" {
+		query, err := os.ReadFile(graphQLQueryFile)
+		if err != nil {
+			log.Fatalf("Erro ao ler a query GraphQL: %v", err)
+		}
+		clientOptions.GraphQL.Query = string(query)
+		clientOptions.GraphQL.Variables = json.RawMessage(graphQLVariables)
+	}
+	if oauth2Scopes != "" {
+		clientOptions.Auth.OAuth2.Scopes = strings.Split(oauth2Scopes, ",")
+	}
+	if tlsCiphers != "" {
+		clientOptions.TLS.Ciphers = strings.Split(tlsCiphers, ",")
+	}
+
+	// por padrão mantém uma conexão ociosa por worker, considerando o maior degrau da busca
+	workers := config.Concurrency
+	if search.Mode != "" {
+		workers = max(workers, search.Max)
+	}
+	if clientOptions.MaxIdleConns == 0 {
+		clientOptions.MaxIdleConns = workers
+	}
+	if clientOptions.MaxIdleConnsPerHost == 0 {
+		clientOptions.MaxIdleConnsPerHost = workers
+	}
+
+	// inicializa o client de acordo com o protocolo do alvo
+	var client domain.Executor
+	switch {
+	case grpcclient.IsGRPCURL(config.URL):
+		grpcOptions.Timeout = clientOptions.Timeout
+		grpcOptions.Insecure = clientOptions.TLS.Insecure
+		grpcClient, err := grpcclient.NewClient(config.URL, grpcOptions)
+		if err != nil {
+			log.Fatalf("Erro ao configurar o cliente gRPC: %v", err)
+		}
+		defer grpcClient.Close()
+		client = grpcClient
+	case rawsocket.IsRawURL(config.URL):
+		rawOptions := rawsocket.DefaultOptions()
+		rawOptions.ConnectTimeout = clientOptions.ConnectTimeout
+		rawOptions.Timeout = clientOptions.Timeout
+		rawOptions.DisableKeepAlives = clientOptions.DisableKeepAlives
+		rawOptions.ResponseBytes = responseBytes
+		if payload != "" {
+			rawOptions.Payload = []byte(unescape(payload))
+		}
+		if payloadFile != "" {
+			content, err := os.ReadFile(payloadFile)
+			if err != nil {
+				log.Fatalf("Erro ao ler o payload: %v", err)
+			}
+			rawOptions.Payload = content
+		}
+		if delimiter != "" {
+			rawOptions.Delimiter = []byte(unescape(delimiter))
+		}
+		rawClient, err := rawsocket.NewClient(rawOptions)
+		if err != nil {
+			log.Fatalf("Erro ao configurar o cliente TCP/UDP: %v", err)
+		}
+		client = rawClient
+	case sseMode:
+		// os streams usam as mesmas opcoes de conexao, TLS, proxy e autenticacao do HTTP
+		sseOptions.HTTP = clientOptions
+		sseClient, err := sse.NewClient(sseOptions)
+		if err != nil {
+			log.Fatalf("Erro ao configurar o cliente SSE: %v", err)
+		}
+		client = sseClient
+	case websocket.IsWebSocketURL(config.URL):
+		if messagesFile != "" {
+			content, err := os.ReadFile(messagesFile)
+			if err != nil {
+				log.Fatalf("Erro ao ler o roteiro de mensagens: %v", err)
+			}
+			for _, line := range strings.Split(string(content), "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					messages = append(messages, line)
+				}
+			}
+		}
+
+		wsOptions := websocket.DefaultOptions()
+		wsOptions.Timeout = clientOptions.Timeout
+		wsOptions.ConnectTimeout = clientOptions.ConnectTimeout
+		wsOptions.Insecure = clientOptions.TLS.Insecure
+		if len(messages) > 0 {
+			wsOptions.Messages = messages
+		}
+		wsClient, err := websocket.NewClient(wsOptions)
+		if err != nil {
+			log.Fatalf("Erro ao configurar o cliente WebSocket: %v", err)
+		}
+		client = wsClient
+	default:
+		httpClient, err := httpclient.NewClient(clientOptions)
+		if err != nil {
+			log.Fatalf("Erro ao configurar o cliente HTTP: %v", err)
+		}
+		client = httpClient
+	}
 
-	// inicializa o client http
-	httpClient := httpclient.NewClient()
 	// inicializa o reporter que irá imprimir o resultado do teste
 	reporter := usecases.NewReporter()
-	// inicializa o loadTester com o HTTPClient e o Reporter
-	loadTester := usecases.NewLoadTesterUseCase(httpClient, reporter)
+
+	// escolhe como o progresso é exibido de acordo com o terminal e as flags
+	output := cli.DetectOutput(noColor, quiet)
+	observers := []domain.ProgressObserver{cli.NewProgressObserver(output, dashboard)}
+	// na busca cada degrau e um teste completo, entao o progresso e uma linha por degrau
+	if search.Mode != "" {
+		observers[0] = cli.NewSearchProgress(os.Stdout, output, search.Mode)
+	}
+
+	// grava os eventos estruturados do teste
+	var events *observer.JSONLines
+	if eventsFile != "" {
+		f, err := os.Create(eventsFile)
+		if err != nil {
+			log.Fatalf("Erro ao criar arquivo de eventos: %v", err)
+		}
+		defer f.Close()
+		events = observer.NewJSONLines(f)
+		observers = append(observers, events)
+	}
+
+	// expõe as métricas do teste enquanto ele executa
+	if metricsAddr != "" {
+		collector := metrics.NewPrometheusCollector()
+		observers = append(observers, collector)
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", collector)
+		srv := &http.Server{Addr: metricsAddr, Handler: mux}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Erro ao iniciar servidor de métricas: %v", err)
+			}
+		}()
+		defer srv.Close()
+	}
+
+	// inicializa o reporter
+	presenter := cli.NewReportPresenter(os.Stdout, output)
+
+	if search.Mode != "" {
+		searcher := usecases.NewThroughputSearchUseCase(client, reporter, observer.NewMulti(observers...))
+		searchReport, err := searcher.Search(context.Background(), config, search)
+		if err != nil {
+			log.Fatal(err)
+		}
+		presenter.PresentSearch(searchReport)
+		warnEventsError(events)
+		return
+	}
+
+	// inicializa o loadTester com o client, o Reporter e os observers
+	loadTester := usecases.NewLoadTesterUseCase(client, reporter, observer.NewMulti(observers...))
 
 	// executa o teste de carga
 	report, err := loadTester.Execute(config)
@@ -36,9 +303,154 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// inicializa o reporter
-	presenter := cli.NewReportPresenter()
-
 	// imprime o resultado do teste de carga
 	presenter.Present(report)
+	warnEventsError(events)
+}
+
+// avisa quando parte dos eventos estruturados nao pode ser gravada, ex: disco cheio
+func warnEventsError(events *observer.JSONLines) {
+	if events == nil {
+		return
+	}
+	if err := events.Err(); err != nil {
+		log.Printf("Erro ao gravar eventos: %v", err)
+	}
+}
+
+// interpreta escapes como \n e \r\n nos valores de --payload e --delimiter
+func unescape(value string) string {
+	unquoted, err := strconv.Unquote(`"` + strings.ReplaceAll(value, `"`, `\"`) + `"`)
+	if err != nil {
+		return value
+	}
+	return unquoted
+}
+
+// flag repetível que acumula os valores informados, usada em --resolve e --message
+type listFlag struct {
+	entries *[]string
+}
+
+func (f *listFlag) String() string {
+	if f.entries == nil {
+		return ""
+	}
+	return strings.Join(*f.entries, ",")
+}
+
+func (f *listFlag) Set(value string) error {
+	*f.entries = append(*f.entries, value)
+	return nil
+}
+
+// lista de status e classes de erro que disparam retries, ex: 503,429,timeout
+type retryOnFlag struct {
+	policy *domain.RetryPolicy
+}
+
+func (f *retryOnFlag) String() string {
+	if f.policy == nil {
+		return ""
+	}
+	values := make([]string, 0, len(f.policy.Statuses)+len(f.policy.ErrorClasses))
+	for _, status := range f.policy.Statuses {
+		values = append(values, strconv.Itoa(status))
+	}
+	return strings.Join(append(values, f.policy.ErrorClasses...), ",")
+}
+
+func (f *retryOnFlag) Set(value string) error {
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if status, err := strconv.Atoi(entry); err == nil {
+			if status < 100 || status > 599 {
+				return fmt.Errorf("invalid status %d", status)
+			}
+			f.policy.Statuses = append(f.policy.Statuses, status)
+		} else if entry != "" {
+			if !slices.Contains(domain.ErrorClasses, entry) {
+				return fmt.Errorf("unknown error class %q, expected a status or one of %s", entry, strings.Join(domain.ErrorClasses, ", "))
+			}
+			f.policy.ErrorClasses = append(f.policy.ErrorClasses, entry)
+		}
+	}
+	return nil
+}
+
+// think time no formato distribuicao:parametros, ex: constant:1s, uniform:1s:3s, normal:2s:500ms
+// ou exponential:2s
+type thinkTimeFlag struct {
+	think *domain.ThinkTime
+}
+
+func (f *thinkTimeFlag) String() string {
+	if f.think == nil || f.think.Distribution == "" {
+		return ""
+	}
+	switch f.think.Distribution {
+	case domain.ThinkUniform:
+		return fmt.Sprintf("%s:%v:%v", f.think.Distribution, f.think.Min, f.think.Max)
+	case domain.ThinkNormal:
+		return fmt.Sprintf("%s:%v:%v", f.think.Distribution, f.think.Mean, f.think.StdDev)
+	}
+	return fmt.Sprintf("%s:%v", f.think.Distribution, f.think.Mean)
+}
+
+func (f *thinkTimeFlag) Set(value string) error {
+	parts := strings.Split(value, ":")
+	durations := make([]time.Duration, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		d, err := time.ParseDuration(part)
+		if err != nil {
+			return err
+		}
+		durations = append(durations, d)
+	}
+
+	think := domain.ThinkTime{Distribution: parts[0]}
+	switch {
+	case (think.Distribution == domain.ThinkConstant || think.Distribution == domain.ThinkExponential) && len(durations) == 1:
+		think.Mean = durations[0]
+	case think.Distribution == domain.ThinkUniform && len(durations) == 2:
+		think.Min, think.Max = durations[0], durations[1]
+	case think.Distribution == domain.ThinkNormal && len(durations) == 2:
+		think.Mean, think.StdDev = durations[0], durations[1]
+	default:
+		return fmt.Errorf("invalid think time %q", value)
+	}
+	if err := think.Validate(); err != nil {
+		return err
+	}
+	*f.think = think
+	return nil
+}
+
+// aceita um numero de redirects ou none; none e 0 desabilitam o seguimento de redirects e,
+// sem a flag, o cliente usa o padrao
+type redirectsFlag struct {
+	max **int
+}
+
+func (f *redirectsFlag) String() string {
+	if f.max == nil || *f.max == nil {
+		return "10"
+	}
+	if **f.max == 0 {
+		return "none"
+	}
+	return strconv.Itoa(**f.max)
+}
+
+func (f *redirectsFlag) Set(value string) error {
+	n := 0
+	if value != "none" {
+		var err error
+		n, err = strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid value %q, expected a number or none", value)
+		}
+	}
+	*f.max = &n
+	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"log"
 	"math/rand"
 	"net/http"
@@ -128,6 +130,11 @@ func main() {
 		IdleTimeout:  15 * time.Second,
 	}
 
+	// com H2C=true o servidor tambem aceita HTTP/2 sem TLS (prior knowledge)
+	if os.Getenv("H2C") == "true" {
+		srv.Handler = h2c.NewHandler(http.DefaultServeMux, &http2.Server{})
+	}
+
 	// carregas configuracoes - pesos por http code
 	statusWeights := parseStatusWeights(os.Getenv("STATUS_WEIGHTS"))
 
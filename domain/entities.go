@@ -3,22 +3,122 @@ package domain
 import "time"
 
 type TestConfig struct {
-	URL         string // url que será testada
-	Requests    int    // numero de requests que serao enviados
-	Concurrency int    // numero de workers que serao usados para enviar as requisicoes
+	URL         string        // url que será testada
+	Requests    int           // numero de requests que serao enviados
+	Concurrency int           // numero de workers que serao usados para enviar as requisicoes
+	Rate        int           // limite de requisicoes por segundo, 0 para nao limitar
+	Retry       RetryPolicy   // novas tentativas das requisicoes que falharem
+	ThinkTime   ThinkTime     // pausa de cada worker entre iteracoes, fora da latencia medida
+	Pacing      time.Duration // intervalo minimo entre o inicio de iteracoes do mesmo worker, 0 para nao limitar
+}
+
+// requisicao abstrata enviada pelo use case; os detalhes de cada protocolo (metodo, corpo,
+// mensagens) sao configurados no executor
+type Request struct {
+	Target   string // alvo do teste, ex: https://, ws://, grpc:// ou tcp://
+	WorkerID int    // worker que envia a requisicao
 }
 
 type TestResult struct {
-	Duration time.Duration // duracao do teste
-	Status   int
-	Error    error
+	Duration   time.Duration // duracao do teste
+	Status     int
+	Error      error
+	ConnOpened bool   // a requisicao abriu uma nova conexao
+	ConnReused bool   // a requisicao reutilizou uma conexao ociosa
+	Protocol   string // protocolo negociado, ex: HTTP/1.1 ou HTTP/2.0
+	RemoteIP   string // endereco IP conectado
+
+	ConnectDuration time.Duration // tempo de abertura da conexao, zero quando reutilizada ou nao medido pelo adaptador
+	Disconnected    bool          // a conexao foi encerrada pelo servidor ou pela rede durante a requisicao
+
+	Retries      int           // novas tentativas feitas alem da primeira
+	RetryLatency time.Duration // tempo gasto nas tentativas anteriores e nas esperas entre elas
+
+	// atributos especificos do protocolo, agregados por valor no relatorio, ex: grpc.status ou tls
+	Attributes map[string]string
+
+	// detalhes de protocolo que nao cabem em Attributes: o relatorio soma ou calcula a media
+	// destes valores (stream, redirects, bytes, handshake), combina InitialStatus com Status nas
+	// cadeias de redirect e conta cada uma das mensagens GraphQL, e um atributo e um unico texto
+	// contado por valor
+	Stream        *StreamStats // metricas do stream SSE, nil nas demais requisicoes
+	GraphQLErrors []string     // mensagens do array errors de uma resposta GraphQL
+
+	Redirects     int // redirects seguidos ate a resposta final
+	InitialStatus int // status da primeira resposta da cadeia, igual a Status quando nao houve redirect
+
+	WireBytes    int64 // bytes do corpo recebidos na conexao
+	DecodedBytes int64 // bytes do corpo apos a descompressao
+
+	TLSHandshake time.Duration // duracao do handshake, registrada pela primeira requisicao que usa a conexao
+}
+
+// indica se a requisicao terminou sem erro e com status 2xx; a mesma regra define o sucesso no
+// relatorio, nos retries, na taxa de erros da busca de vazao maxima e no dashboard
+func (r *TestResult) Succeeded() bool {
+	return r.Error == nil && r.Status >= 200 && r.Status < 300
 }
 
+// atributos preenchidos pelo adaptador HTTP que o relatorio exibe em secoes proprias
+const (
+	AttributeTLS             = "tls"                   // versao TLS e cipher suite, ex: TLS 1.3 TLS_AES_128_GCM_SHA256
+	AttributeContentEncoding = "http.content_encoding" // Content-Encoding da resposta, identity quando nao comprimida
+)
+
 type TestReport struct {
-	TotalDuration   time.Duration // duracao total do teste
-	TotalRequests   int           // total de requisicoes disparadas contra o alvo
-	SuccessRequests int           // requisicoes com sucesso
-	StatusDistrib   map[int]int   // map de inteiros que armazens o resultado entre HTTP Status Code
-	ErrorCount      int           // numero de erros
-	AverageDuration time.Duration // duracao media de uma requisicao
+	TotalDuration    time.Duration             // duracao total do teste
+	TotalRequests    int                       // total de requisicoes disparadas contra o alvo
+	SuccessRequests  int                       // requisicoes com sucesso (TestResult.Succeeded)
+	StatusDistrib    map[int]int               // map de inteiros que armazens o resultado entre HTTP Status Code
+	ErrorCount       int                       // numero de erros
+	ErrorsByClass    map[string]int            // erros agrupados por classe (timeout, connection, tls...)
+	AverageDuration  time.Duration             // duracao media de uma requisicao
+	P50              time.Duration             // mediana da duracao das requisicoes
+	P95              time.Duration             // percentil 95 da duracao das requisicoes
+	P99              time.Duration             // percentil 99 da duracao das requisicoes
+	ConnsOpened      int                       // conexoes novas abertas durante o teste
+	ConnsReused      int                       // requisicoes que reutilizaram uma conexao ociosa
+	ProtocolDistrib  map[string]int            // requisicoes por protocolo negociado
+	TLSHandshakes    int                       // handshakes TLS realizados
+	AvgTLSHandshake  time.Duration             // duracao media dos handshakes TLS
+	IPStats          map[string]IPStats        // latencia e erros por endereco IP conectado
+	Redirected       int                       // requisicoes que seguiram ao menos um redirect
+	RedirectHops     int                       // total de redirects seguidos
+	RedirectChains   map[string]int            // status inicial -> final das requisicoes redirecionadas, ex: "301 -> 200"
+	WireBytes        int64                     // bytes de corpo recebidos na conexao
+	DecodedBytes     int64                     // bytes de corpo apos a descompressao
+	CompressionRatio float64                   // DecodedBytes / WireBytes, 1 quando nada foi comprimido
+	Throughput       float64                   // requisicoes (ou mensagens) concluidas por segundo
+	AvgConnectTime   time.Duration             // tempo medio de abertura das conexoes que informam ConnectDuration
+	Disconnects      int                       // conexoes encerradas durante o teste
+	AttributeDistrib map[string]map[string]int // requisicoes por valor de cada atributo do protocolo
+	Streams          int                       // streams SSE que chegaram a ser abertos
+	Events           int                       // eventos SSE recebidos
+	EventsPerSecond  float64                   // eventos recebidos por segundo de teste
+	AvgFirstEvent    time.Duration             // tempo medio ate o primeiro evento de cada stream
+	AvgEventGap      time.Duration             // intervalo medio entre eventos consecutivos
+	MaxEventGap      time.Duration             // maior intervalo entre eventos consecutivos
+	AvgStreamHeld    time.Duration             // tempo medio que cada stream ficou aberto
+	GraphQLErrors    map[string]int            // erros GraphQL agrupados pela mensagem
+	FirstAttemptOK   int                       // requisicoes com sucesso ja na primeira tentativa
+	Retried          int                       // requisicoes que precisaram de novas tentativas
+	Retries          int                       // total de novas tentativas
+	RetriesRecovered int                       // requisicoes repetidas que terminaram com sucesso
+	AvgRetryLatency  time.Duration             // latencia extra media das requisicoes repetidas
+}
+
+// metricas de um stream SSE mantido aberto durante a requisicao
+type StreamStats struct {
+	Events     int           // eventos recebidos
+	FirstEvent time.Duration // tempo ate o primeiro evento
+	TotalGap   time.Duration // soma dos intervalos entre eventos consecutivos
+	MaxGap     time.Duration // maior intervalo entre eventos consecutivos
+	Held       time.Duration // tempo que o stream ficou aberto
+}
+
+// metricas das requisicoes enviadas para um mesmo endereco IP
+type IPStats struct {
+	Requests        int
+	Errors          int
+	AverageDuration time.Duration
 }
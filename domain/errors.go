@@ -5,4 +5,39 @@ import "errors"
 var (
 	ErrTimeout    = errors.New("request timeout")
 	ErrConnection = errors.New("connection error")
+	ErrTLS        = errors.New("tls error")
+	ErrProxy      = errors.New("proxy error")
+	ErrDNS        = errors.New("dns error")
+	ErrAuth       = errors.New("authentication error")
+	ErrDecode     = errors.New("response decoding error")
+	ErrGraphQL    = errors.New("graphql error")
 )
+
+// classes retornadas por ErrorClass, aceitas nas politicas de retry
+var ErrorClasses = []string{"proxy", "auth", "dns", "timeout", "connection", "tls", "decode", "graphql", "other"}
+
+// classifica um erro em uma categoria estavel, usada nas metricas e no relatorio
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrProxy):
+		return "proxy"
+	case errors.Is(err, ErrAuth):
+		return "auth"
+	case errors.Is(err, ErrDNS):
+		return "dns"
+	case errors.Is(err, ErrTimeout):
+		return "timeout"
+	case errors.Is(err, ErrConnection):
+		return "connection"
+	case errors.Is(err, ErrTLS):
+		return "tls"
+	case errors.Is(err, ErrDecode):
+		return "decode"
+	case errors.Is(err, ErrGraphQL):
+		return "graphql"
+	default:
+		return "other"
+	}
+}
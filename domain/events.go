@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+type EventType string
+
+const (
+	EventStarted        EventType = "started"         // o teste comecou, Config preenchido
+	EventWorkerStarted  EventType = "worker_started"  // um worker comecou a enviar requisicoes
+	EventWorkerFinished EventType = "worker_finished" // um worker terminou, apos os request_done dele
+	EventRequestDone    EventType = "request_done"    // uma requisicao terminou, Result preenchido
+	EventStageChanged   EventType = "stage_changed"   // o teste mudou de etapa, Stage preenchido
+	EventFinished       EventType = "finished"        // o teste terminou, Report preenchido
+	EventStepDone       EventType = "step_done"       // um degrau da busca de vazao maxima terminou, Step preenchido
+)
+
+// etapas pelas quais um teste passa
+const (
+	StageRunning   = "running"   // workers enviando requisicoes
+	StageReporting = "reporting" // resultados sendo consolidados no relatorio
+)
+
+// evento emitido pelo LoadTester durante o ciclo de vida de um teste
+type Event struct {
+	Type     EventType
+	Time     time.Time
+	Config   *TestConfig
+	WorkerID int
+	Result   *TestResult
+	Stage    string
+	Report   *TestReport
+	Step     *SearchStep
+}
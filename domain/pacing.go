@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// distribuicoes aceitas pelo think time
+const (
+	ThinkConstant    = "constant"
+	ThinkUniform     = "uniform"
+	ThinkNormal      = "normal"
+	ThinkExponential = "exponential"
+)
+
+// pausa de cada worker entre uma iteracao e a proxima, como um usuario lendo a resposta;
+// Distribution vazia desabilita
+type ThinkTime struct {
+	Distribution string        // constant, uniform, normal ou exponential
+	Mean         time.Duration // valor constante ou media das distribuicoes normal e exponencial
+	StdDev       time.Duration // desvio padrao da distribuicao normal
+	Min          time.Duration // limite inferior da distribuicao uniforme
+	Max          time.Duration // limite superior da distribuicao uniforme
+}
+
+// verifica se a distribuicao e conhecida e os parametros sao coerentes
+func (t ThinkTime) Validate() error {
+	switch t.Distribution {
+	case "":
+		return nil
+	case ThinkConstant, ThinkExponential:
+		if t.Mean < 0 {
+			return fmt.Errorf("think time mean must not be negative")
+		}
+	case ThinkNormal:
+		if t.Mean < 0 || t.StdDev < 0 {
+			return fmt.Errorf("think time mean and stddev must not be negative")
+		}
+	case ThinkUniform:
+		if t.Min < 0 || t.Max < t.Min {
+			return fmt.Errorf("think time range must satisfy 0 <= min <= max")
+		}
+	default:
+		return fmt.Errorf("unknown think time distribution %q", t.Distribution)
+	}
+	return nil
+}
+
+// sorteia uma pausa segundo a distribuicao configurada; valores negativos da distribuicao
+// normal viram zero
+func (t ThinkTime) Sample() time.Duration {
+	var d time.Duration
+	switch t.Distribution {
+	case ThinkConstant:
+		d = t.Mean
+	case ThinkUniform:
+		d = t.Min + rand.N(t.Max-t.Min+1)
+	case ThinkNormal:
+		d = t.Mean + time.Duration(rand.NormFloat64()*float64(t.StdDev))
+	case ThinkExponential:
+		d = time.Duration(rand.ExpFloat64() * float64(t.Mean))
+	}
+	return max(d, 0)
+}
+
+// momento em que o worker inicia a proxima iteracao, dada uma iteracao entre start e end: o
+// pacing conta a partir do inicio da iteracao anterior e o think time a partir do fim, valendo
+// o que terminar por ultimo
+func (c TestConfig) NextIteration(start, end time.Time) time.Time {
+	next := end.Add(c.ThinkTime.Sample())
+	if paced := start.Add(c.Pacing); paced.After(next) {
+		next = paced
+	}
+	return next
+}
@@ -1,21 +1,47 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type LoadTester interface {
 	Execute(config TestConfig) (*TestReport, error)
 }
 
-type HTTPClient interface {
-	Get(url string) (*TestResult, error)
+// executa uma requisicao do teste em qualquer protocolo (HTTP, WebSocket, gRPC, TCP...).
+// Falhas do alvo sao registradas em TestResult.Error; o erro retornado indica que o proprio
+// executor nao conseguiu executar a requisicao
+type Executor interface {
+	Execute(ctx context.Context, req Request) (*TestResult, error)
+}
+
+// permite usar uma funcao comum como Executor
+type ExecutorFunc func(ctx context.Context, req Request) (*TestResult, error)
+
+func (f ExecutorFunc) Execute(ctx context.Context, req Request) (*TestResult, error) {
+	return f(ctx, req)
+}
+
+// executores que mantem estado por usuario virtual (cookies, conexoes) criam uma sessao
+// independente para cada worker; sessoes que implementam io.Closer sao fechadas quando o
+// worker termina
+type SessionFactory interface {
+	NewSession() Executor
 }
 
 type Reporter interface {
 	GenerateReport(results []TestResult, duration time.Duration) *TestReport
 }
 
-type ProgressTracker interface {
-	Start()
-	Stop()
-	IncrementWorker(workerID int)
+// recebe os eventos do teste; pode ser chamado de varias goroutines ao mesmo tempo
+type ProgressObserver interface {
+	OnEvent(event Event)
+}
+
+// permite usar uma funcao comum como ProgressObserver
+type ObserverFunc func(event Event)
+
+func (f ObserverFunc) OnEvent(event Event) {
+	f(event)
 }
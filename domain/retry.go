@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// politica de novas tentativas de uma requisicao; MaxRetries zero desabilita
+type RetryPolicy struct {
+	MaxRetries   int           // novas tentativas alem da primeira
+	Statuses     []int         // status que disparam nova tentativa, ex: 502, 503
+	ErrorClasses []string      // classes de erro que disparam nova tentativa, ex: timeout, connection
+	BaseDelay    time.Duration // espera antes da primeira nova tentativa, dobrada a cada tentativa
+	MaxDelay     time.Duration // limite da espera entre tentativas, zero para nao limitar
+}
+
+// status e classes de erro usados quando a politica nao define nenhum
+var (
+	DefaultRetryStatuses     = []int{502, 503, 504}
+	DefaultRetryErrorClasses = []string{"timeout", "connection"}
+)
+
+// indica se o resultado deve ser repetido pela politica
+func (p RetryPolicy) Retryable(result *TestResult) bool {
+	statuses, classes := p.Statuses, p.ErrorClasses
+	if len(statuses) == 0 && len(classes) == 0 {
+		statuses, classes = DefaultRetryStatuses, DefaultRetryErrorClasses
+	}
+	if result.Error != nil {
+		return slices.Contains(classes, ErrorClass(result.Error))
+	}
+	return slices.Contains(statuses, result.Status)
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// grandezas que o modo de busca de vazao maxima pode aumentar
+const (
+	SearchConcurrency = "concurrency" // aumenta o numero de workers
+	SearchRate        = "rate"        // aumenta o limite de requisicoes por segundo
+)
+
+// limites que encerram a busca quando ultrapassados
+const (
+	BreachP99       = "p99"
+	BreachErrorRate = "error_rate"
+)
+
+// configuracao da busca de vazao maxima: cada degrau executa um teste completo com o nivel
+// Start, Start+Step, ... ate Max ou ate um limite ser ultrapassado
+type SearchConfig struct {
+	Mode         string        // concurrency ou rate
+	Start        int           // nivel do primeiro degrau
+	Step         int           // incremento entre degraus
+	Max          int           // maior nivel testado
+	MaxP99       time.Duration // p99 maximo sustentavel, zero ignora
+	MaxErrorRate float64       // percentual (0 a 100) maximo de requisicoes sem sucesso, ex: 1 para 1%; 0 nao tolera falhas
+}
+
+// verifica se o modo e conhecido e os degraus sao coerentes
+func (s SearchConfig) Validate() error {
+	switch {
+	case s.Mode != SearchConcurrency && s.Mode != SearchRate:
+		return fmt.Errorf("unknown search mode %q", s.Mode)
+	case s.Start <= 0 || s.Step <= 0:
+		return fmt.Errorf("search start and step must be positive")
+	case s.Max < s.Start:
+		return fmt.Errorf("search max must not be lower than start")
+	case s.MaxP99 < 0 || s.MaxErrorRate < 0:
+		return fmt.Errorf("search thresholds must not be negative")
+	}
+	return nil
+}
+
+// resultado de um degrau da busca
+type SearchStep struct {
+	Level      int           // concorrencia ou taxa usada no degrau
+	Throughput float64       // requisicoes concluidas por segundo
+	P99        time.Duration // percentil 99 da latencia
+	ErrorRate  float64       // percentual (0 a 100) de requisicoes sem sucesso
+	Breach     string        // limite ultrapassado (p99 ou error_rate), vazio quando sustentavel
+	Report     *TestReport   // relatorio completo do degrau
+}
+
+type SearchReport struct {
+	Config      SearchConfig
+	Steps       []SearchStep // degraus executados, em ordem
+	Sustainable *SearchStep  // ultimo degrau sustentavel, nil quando o primeiro ja ultrapassou os limites
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// retorna o percentil p de uma lista de duracoes ja ordenada (nearest rank); usado pelo
+// relatorio final e pelas metricas moveis do dashboard
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p/100+0.5) - 1
+	return sorted[max(0, min(idx, len(sorted)-1))]
+}
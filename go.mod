@@ -2,11 +2,21 @@ module go-expert-stress-test
 
 go 1.23.2
 
-require github.com/jedib0t/go-pretty/v6 v6.6.3
+require (
+	github.com/andybalholm/brotli v1.2.0
+	github.com/gorilla/websocket v1.5.3
+	github.com/jedib0t/go-pretty/v6 v6.6.3
+	github.com/klauspost/compress v1.18.0
+	golang.org/x/net v0.43.0
+	golang.org/x/term v0.34.0
+	google.golang.org/grpc v1.75.1
+	google.golang.org/protobuf v1.36.10
+)
 
 require (
 	github.com/mattn/go-runewidth v0.0.15 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
-	golang.org/x/sys v0.17.0 // indirect
-	golang.org/x/term v0.17.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
 )
@@ -0,0 +1,205 @@
+// Package grpcclient implementa o adaptador de teste de carga para chamadas gRPC unarias,
+// montando as mensagens dinamicamente a partir de um descriptor set ou da reflection do
+// servidor, sem codigo gerado.
+package grpcclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/netutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// prefixos dos alvos gRPC: grpc:// em texto puro (h2c) e grpcs:// com TLS
+const (
+	plaintextScheme = "grpc://"
+	tlsScheme       = "grpcs://"
+)
+
+// atributo do resultado com o codigo de status gRPC original
+const statusAttribute = "grpc.status"
+
+// parametros da chamada gRPC
+type Options struct {
+	Method        string        // metodo no formato pacote.Servico/Metodo
+	Request       string        // mensagem de requisicao em JSON, vazio envia a mensagem vazia
+	DescriptorSet string        // arquivo FileDescriptorSet (protoc --descriptor_set_out); vazio usa reflection
+	Timeout       time.Duration // tempo maximo de cada chamada
+	Insecure      bool          // nao valida o certificado do servidor em grpcs://
+}
+
+// limita cada chamada para que um servidor que nao responde nao prenda os workers
+func DefaultOptions() Options {
+	return Options{Timeout: 30 * time.Second}
+}
+
+// indica se a url deve ser testada com o adaptador gRPC
+func IsGRPCURL(url string) bool {
+	return strings.HasPrefix(url, plaintextScheme) || strings.HasPrefix(url, tlsScheme)
+}
+
+// cliente gRPC; a conexao HTTP/2 e compartilhada entre os workers, que multiplexam as chamadas
+type Client struct {
+	conn     *grpc.ClientConn
+	method   string // /pacote.Servico/Metodo
+	request  proto.Message
+	response protoreflect.MessageDescriptor
+	timeout  time.Duration
+}
+
+// conecta ao alvo e resolve o metodo no descriptor set ou pela reflection do servidor; retorna
+// erro quando o metodo nao existe ou o JSON da requisicao nao corresponde a mensagem
+func NewClient(target string, opts Options) (*Client, error) {
+	addr, creds := dialTarget(target, opts.Insecure)
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	service, method, err := splitMethod(opts.Method)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	var descriptor protoreflect.MethodDescriptor
+	if opts.DescriptorSet != "" {
+		descriptor, err = methodFromDescriptorSet(opts.DescriptorSet, service, method)
+	} else {
+		descriptor, err = methodFromReflection(conn, service, method)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if descriptor.IsStreamingClient() || descriptor.IsStreamingServer() {
+		conn.Close()
+		return nil, fmt.Errorf("method %s is streaming, only unary methods are supported", opts.Method)
+	}
+
+	request := dynamicpb.NewMessage(descriptor.Input())
+	if opts.Request != "" {
+		if err := protojson.Unmarshal([]byte(opts.Request), request); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("parsing request for %s: %w", descriptor.Input().FullName(), err)
+		}
+	}
+
+	return &Client{
+		conn:     conn,
+		method:   "/" + service + "/" + method,
+		request:  request,
+		response: descriptor.Output(),
+		timeout:  opts.Timeout,
+	}, nil
+}
+
+// fecha a conexao com o servidor
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// executa a chamada unaria no alvo configurado em NewClient. O status gRPC entra na
+// distribuicao de status convertido para o codigo HTTP equivalente, e o codigo original
+// fica no atributo grpc.status; DeadlineExceeded e Unavailable tambem contam como erros de
+// timeout e conexao. Canceled retorna o erro do contexto, para que as chamadas interrompidas
+// pelo fim do teste sejam descartadas em vez de contar como respostas
+func (c *Client) Execute(ctx context.Context, _ domain.Request) (*domain.TestResult, error) {
+	result := &domain.TestResult{Protocol: "grpc"}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var p peer.Peer
+	response := dynamicpb.NewMessage(c.response)
+
+	start := time.Now()
+	err := c.conn.Invoke(ctx, c.method, c.request, response, grpc.Peer(&p))
+	result.Duration = time.Since(start)
+
+	if p.Addr != nil {
+		result.RemoteIP = netutil.HostOf(p.Addr.String())
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		result.Error = fmt.Errorf("%w: %v", domain.ErrConnection, err)
+		return result, nil
+	}
+
+	result.Attributes = map[string]string{statusAttribute: st.Code().String()}
+	result.Status = httpStatusFromCode(st.Code())
+	switch st.Code() {
+	case codes.DeadlineExceeded:
+		result.Error = fmt.Errorf("%w: %v", domain.ErrTimeout, err)
+	case codes.Unavailable:
+		result.Error = fmt.Errorf("%w: %v", domain.ErrConnection, err)
+	case codes.Canceled:
+		result.Error = fmt.Errorf("%w: %v", context.Canceled, err)
+	}
+	return result, nil
+}
+
+// separa o endereco do alvo e escolhe as credenciais de transporte pelo esquema
+func dialTarget(target string, insecureTLS bool) (string, credentials.TransportCredentials) {
+	if strings.HasPrefix(target, tlsScheme) {
+		return strings.TrimPrefix(target, tlsScheme), credentials.NewTLS(&tls.Config{InsecureSkipVerify: insecureTLS})
+	}
+	return strings.TrimPrefix(target, plaintextScheme), insecure.NewCredentials()
+}
+
+// aceita pacote.Servico/Metodo, com ou sem a barra inicial
+func splitMethod(fullMethod string) (service, method string, err error) {
+	service, method, found := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !found || service == "" || method == "" {
+		return "", "", errors.New("grpc method must be in the format package.Service/Method")
+	}
+	return service, method, nil
+}
+
+// conversao dos codigos gRPC para HTTP, a mesma usada pelo grpc-gateway
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
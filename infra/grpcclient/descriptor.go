@@ -0,0 +1,95 @@
+package grpcclient
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"os"
+	"time"
+)
+
+// tempo maximo para consultar a reflection do servidor antes do teste
+const reflectionTimeout = 10 * time.Second
+
+// carrega o metodo de um FileDescriptorSet gerado com protoc --include_imports --descriptor_set_out
+func methodFromDescriptorSet(path, service, method string) (protoreflect.MethodDescriptor, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading descriptor set: %w", err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(content, &set); err != nil {
+		return nil, fmt.Errorf("parsing descriptor set: %w", err)
+	}
+	return findMethod(&set, service, method)
+}
+
+// consulta a reflection do servidor pelo arquivo que define o servico e suas dependencias
+func methodFromReflection(conn *grpc.ClientConn, service, method string) (protoreflect.MethodDescriptor, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), reflectionTimeout)
+	defer cancel()
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("server reflection: %w", err)
+	}
+	defer stream.CloseSend()
+
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("server reflection: %w", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("server reflection: %w", err)
+	}
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, fmt.Errorf("server reflection: %s", errResp.GetErrorMessage())
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		file := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(raw, file); err != nil {
+			return nil, fmt.Errorf("server reflection: %w", err)
+		}
+		set.File = append(set.File, file)
+	}
+	return findMethod(&set, service, method)
+}
+
+// monta o registro de arquivos e procura o metodo do servico
+func findMethod(set *descriptorpb.FileDescriptorSet, service, method string) (protoreflect.MethodDescriptor, error) {
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("building descriptors: %w", err)
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err == protoregistry.NotFound {
+		return nil, fmt.Errorf("service %s not found", service)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	svc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+	m := svc.Methods().ByName(protoreflect.Name(method))
+	if m == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", method, service)
+	}
+	return m, nil
+}
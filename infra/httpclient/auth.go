@@ -0,0 +1,167 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-expert-stress-test/domain"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// antecedencia maxima com que o token OAuth2 e renovado antes de expirar; tokens curtos
+// sao renovados quando 90% do tempo de vida tiver passado
+const tokenRefreshMargin = 30 * time.Second
+
+// estrategia de autenticacao aplicada a cada requisicao; no maximo uma deve ser informada
+type AuthOptions struct {
+	Basic  string        // credenciais basic no formato usuario:senha
+	Bearer string        // token bearer estatico
+	OAuth2 OAuth2Options // client credentials, renovado durante o teste
+}
+
+type OAuth2Options struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// adiciona as credenciais na requisicao antes de ela ser enviada
+type authenticator interface {
+	apply(req *http.Request) error
+}
+
+// escolhe a estrategia de autenticacao; retorna nil quando nenhuma foi configurada
+func (o AuthOptions) authenticator(tokenClient *http.Client) (authenticator, error) {
+	configured := 0
+	for _, set := range []bool{o.Basic != "", o.Bearer != "", o.OAuth2.TokenURL != ""} {
+		if set {
+			configured++
+		}
+	}
+	if configured > 1 {
+		return nil, errors.New("only one authentication strategy can be configured")
+	}
+
+	switch {
+	case o.Basic != "":
+		username, password, found := strings.Cut(o.Basic, ":")
+		if !found {
+			return nil, errors.New("basic auth must be in the format user:password")
+		}
+		return basicAuth{username: username, password: password}, nil
+	case o.Bearer != "":
+		return bearerAuth{token: o.Bearer}, nil
+	case o.OAuth2.TokenURL != "":
+		if o.OAuth2.ClientID == "" {
+			return nil, errors.New("oauth2 client id is required")
+		}
+		return &clientCredentials{options: o.OAuth2, client: tokenClient}, nil
+	}
+	return nil, nil
+}
+
+type basicAuth struct {
+	username string
+	password string
+}
+
+func (a basicAuth) apply(req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+type bearerAuth struct {
+	token string
+}
+
+func (a bearerAuth) apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// obtem tokens pelo fluxo client credentials e os mantem em cache ate perto da expiracao;
+// as chamadas ao token endpoint usam um cliente proprio e nao entram nas metricas do alvo
+type clientCredentials struct {
+	options OAuth2Options
+	client  *http.Client
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func (a *clientCredentials) apply(req *http.Request) error {
+	token, err := a.currentToken(req.Context())
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrAuth, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// retorna o token em cache ou busca um novo; o mutex garante que apenas um worker
+// renove o token enquanto os demais aguardam
+func (a *clientCredentials) currentToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && time.Now().Before(a.refreshAt) {
+		return a.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(a.options.Scopes) > 0 {
+		form.Set("scope", strings.Join(a.options.Scopes, " "))
+	}
+
+	// o token endpoint pode ser um unix:// proprio, mas nunca herda o socket do alvo
+	ctx = context.WithValue(ctx, socketPathKey{}, nil)
+	req, err := newRequest(ctx, http.MethodPost, a.options.TokenURL, []byte(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(a.options.ClientID), url.QueryEscape(a.options.ClientSecret))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+
+	var body tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return "", errors.New("token endpoint returned no access_token")
+	}
+
+	// sem expires_in o token e considerado valido por uma hora
+	lifetime := time.Duration(body.ExpiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = time.Hour
+	}
+	margin := min(tokenRefreshMargin, lifetime/10)
+
+	a.token = body.AccessToken
+	a.refreshAt = time.Now().Add(lifetime - margin)
+
+	return a.token, nil
+}
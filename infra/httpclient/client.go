@@ -1,41 +1,400 @@
 package httpclient
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/netutil"
+	"io"
+	"net"
 	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
+// parametros do transporte HTTP; valores zero desabilitam o respectivo limite, exceto
+// MaxIdleConnsPerHost, em que zero usa o padrao do net/http (2 conexoes). DefaultOptions
+// dimensiona o pool pelo numero de workers
+type Options struct {
+	Timeout               time.Duration  // tempo maximo da requisicao completa
+	ConnectTimeout        time.Duration  // tempo maximo para abrir a conexao TCP
+	TLSHandshakeTimeout   time.Duration  // tempo maximo do handshake TLS
+	ResponseHeaderTimeout time.Duration  // tempo maximo ate receber os cabecalhos da resposta
+	MaxIdleConns          int            // conexoes ociosas mantidas no total
+	MaxIdleConnsPerHost   int            // conexoes ociosas mantidas por host; zero usa o padrao do net/http (2)
+	DisableKeepAlives     bool           // abre uma nova conexao para cada requisicao
+	HTTP2                 bool           // negocia HTTP/2 via ALPN em conexoes TLS
+	H2C                   bool           // usa HTTP/2 sem TLS com prior knowledge (h2c)
+	TLS                   TLSOptions     // certificados, CAs e parametros de TLS
+	Proxy                 string         // proxy http, https, socks5 ou socks5h; vazio usa HTTPS_PROXY/HTTP_PROXY
+	ProxyUser             string         // credenciais do proxy no formato usuario:senha
+	Resolve               []string       // overrides de DNS no formato host:port:ip, como no curl
+	SpreadIPs             bool           // distribui as conexoes entre todos os enderecos A/AAAA do host
+	Auth                  AuthOptions    // basic, bearer ou OAuth2 client credentials
+	PoolPerWorker         bool           // cada sessao (worker) usa um pool de conexoes proprio
+	MaxRedirects          *int           // redirects seguidos por requisicao; nil usa o padrao (10), 0 nao segue
+	Method                string         // metodo HTTP, padrao GET
+	Body                  []byte         // corpo enviado em cada requisicao
+	BodyEncoding          string         // comprime o corpo com gzip, br ou zstd
+	AcceptEncoding        string         // valor de Accept-Encoding; vazio usa gzip e "none" nao envia o cabecalho
+	GraphQL               GraphQLOptions // envia uma query GraphQL e inspeciona o array errors da resposta
+}
+
+// opcoes padrao, mantendo uma conexao ociosa por worker para que possam ser reutilizadas
+func DefaultOptions(concurrency int) Options {
+	return Options{
+		Timeout:             30 * time.Second,
+		ConnectTimeout:      10 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConns:        concurrency,
+		MaxIdleConnsPerHost: concurrency,
+	}
+}
+
 type Client struct {
 	client *http.Client
+	auth   authenticator
+
+	// proxy usado pelo transporte, para reconhecer respostas geradas pelo proprio proxy
+	proxy func(*http.Request) (*url.URL, error)
+
+	// usados para criar as sessoes dos workers
+	timeout       time.Duration
+	newTransport  func() http.RoundTripper
+	poolPerWorker bool
+	checkRedirect func(req *http.Request, via []*http.Request) error
+
+	method         string
+	body           []byte
+	bodyEncoding   string
+	acceptEncoding string
+	contentType    string
+	graphQL        bool
 }
 
-// instancia um novo cliente com timeout de 30s
-func NewClient() *Client {
+// instancia um novo cliente com o transporte configurado pelas opcoes; retorna erro quando
+// a configuracao TLS ou de proxy e invalida ou os certificados nao podem ser carregados
+func NewClient(opts Options) (*Client, error) {
+	tlsConfig, err := opts.TLS.config()
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := proxyFunc(opts.Proxy, opts.ProxyUser)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.validateH2C(); err != nil {
+		return nil, err
+	}
+
+	method, rawBody, contentType := opts.Method, opts.Body, ""
+	if opts.GraphQL.enabled() {
+		rawBody, err = opts.GraphQL.body()
+		if err != nil {
+			return nil, err
+		}
+		method, contentType = http.MethodPost, "application/json"
+	}
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	body, err := compressBody(rawBody, opts.BodyEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer, err := newDialer(&net.Dialer{
+		Timeout:   opts.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}, opts.Resolve, opts.SpreadIPs)
+	if err != nil {
+		return nil, err
+	}
+
+	// o h2c conecta diretamente ao alvo, sem proxy
+	transportProxy := bypassProxyForUnix(proxy)
+	if opts.H2C {
+		transportProxy = nil
+	}
+
+	newTransport := func() http.RoundTripper {
+		if opts.H2C {
+			return newH2CTransport(dialer, opts)
+		}
+		// a descompressao e feita pelo cliente para que os bytes recebidos possam ser medidos. O
+		// transporte altera o NextProtos da configuracao TLS ao habilitar HTTP/2, entao cada pool
+		// (um por worker com PoolPerWorker) recebe uma copia
+		return &http.Transport{
+			Proxy:                  transportProxy,
+			OnProxyConnectResponse: checkProxyConnect,
+			DialContext:            dialer.DialContext,
+			TLSClientConfig:        tlsConfig.Clone(),
+			TLSHandshakeTimeout:    opts.TLSHandshakeTimeout,
+			ResponseHeaderTimeout:  opts.ResponseHeaderTimeout,
+			MaxIdleConns:           opts.MaxIdleConns,
+			MaxIdleConnsPerHost:    opts.MaxIdleConnsPerHost,
+			DisableKeepAlives:      opts.DisableKeepAlives,
+			IdleConnTimeout:        90 * time.Second,
+			ForceAttemptHTTP2:      opts.HTTP2,
+			DisableCompression:     true,
+		}
+	}
+
+	// o token endpoint usa um cliente separado, com o mesmo dialer (--resolve e unix://), TLS e
+	// proxy, para nao compartilhar conexoes nem metricas com o alvo
+	auth, err := opts.Auth.authenticator(&http.Client{
+		Timeout: opts.Timeout,
+		Transport: &http.Transport{
+			Proxy:                  bypassProxyForUnix(proxy),
+			OnProxyConnectResponse: checkProxyConnect,
+			DialContext:            dialer.DialContext,
+			TLSClientConfig:        tlsConfig,
+			TLSHandshakeTimeout:    opts.TLSHandshakeTimeout,
+			ResponseHeaderTimeout:  opts.ResponseHeaderTimeout,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	maxRedirects, err := redirectLimit(opts.MaxRedirects)
+	if err != nil {
+		return nil, err
+	}
+	redirectPolicy := checkRedirect(maxRedirects)
+
 	return &Client{
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:       opts.Timeout,
+			Transport:     newTransport(),
+			CheckRedirect: redirectPolicy,
 		},
+		auth:          auth,
+		proxy:         transportProxy,
+		timeout:       opts.Timeout,
+		newTransport:  newTransport,
+		poolPerWorker: opts.PoolPerWorker,
+		checkRedirect: redirectPolicy,
+
+		method:         method,
+		body:           body,
+		bodyEncoding:   opts.BodyEncoding,
+		acceptEncoding: acceptEncodingHeader(opts.AcceptEncoding),
+		contentType:    contentType,
+		graphQL:        opts.GraphQL.enabled(),
+	}, nil
+}
+
+// cria um cliente para um usuario virtual, com cookie jar proprio: os cookies recebidos
+// (ex: login) sao enviados apenas nas requisicoes seguintes da mesma sessao. O pool de
+// conexoes e compartilhado, a menos que PoolPerWorker esteja habilitado
+func (c *Client) NewSession() domain.Executor {
+	jar, _ := cookiejar.New(nil)
+
+	transport := c.client.Transport
+	if c.poolPerWorker {
+		transport = c.newTransport()
+	}
+
+	session := *c
+	session.client = &http.Client{
+		Timeout:       c.timeout,
+		Transport:     transport,
+		Jar:           jar,
+		CheckRedirect: c.checkRedirect,
+	}
+	return &session
+}
+
+// fecha as conexoes ociosas do pool proprio da sessao quando PoolPerWorker esta habilitado;
+// o pool compartilhado entre as sessoes continua aberto
+func (c *Client) Close() error {
+	if c.poolPerWorker {
+		c.client.CloseIdleConnections()
 	}
+	return nil
 }
 
-// executa uma requisicao GET e retorna TestResult
-func (c *Client) Get(url string) (*domain.TestResult, error) {
+// executa a requisicao configurada contra o alvo e retorna TestResult; o contexto cancela
+// a requisicao em andamento
+func (c *Client) Execute(ctx context.Context, r domain.Request) (*domain.TestResult, error) {
+	resp, result := c.Open(ctx, r, nil)
+	if result.Error != nil {
+		return result, nil
+	}
+	defer resp.Body.Close()
+
+	// consome o corpo para que a conexao possa voltar ao pool, medindo os bytes recebidos
+	// na conexao e os bytes apos a descompressao; no modo GraphQL o corpo e guardado para
+	// que os erros da resposta possam ser inspecionados
+	var dst io.Writer = io.Discard
+	var body bytes.Buffer
+	if c.graphQL {
+		dst = &body
+	}
+	if err := readBody(resp, result, dst); err != nil {
+		result.Error = err
+		return result, nil
+	}
+
+	// um 200 com erros GraphQL e uma falha, agrupada pelas mensagens de erro
+	if c.graphQL && resp.StatusCode == http.StatusOK {
+		if messages := graphQLErrors(body.Bytes()); len(messages) > 0 {
+			result.GraphQLErrors = messages
+			result.Error = fmt.Errorf("%w: %s", domain.ErrGraphQL, strings.Join(messages, "; "))
+		}
+	}
+	return result, nil
+}
+
+// envia a requisicao configurada, acrescida dos cabecalhos em header, e retorna a resposta sem
+// consumir o corpo, para adaptadores que leem a resposta como stream (ex: SSE). O resultado traz
+// a duracao ate os cabecalhos e as metricas de conexao; quando result.Error e preenchido nao ha
+// resposta, caso contrario o chamador deve fechar o corpo
+func (c *Client) Open(ctx context.Context, r domain.Request, header http.Header) (*http.Response, *domain.TestResult) {
+	result := &domain.TestResult{}
+
+	req, err := newRequest(ctx, c.method, r.Target, c.body)
+	if err != nil {
+		result.Error = classifyError(err)
+		return nil, result
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if c.contentType != "" {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+	if c.bodyEncoding != "" {
+		req.Header.Set("Content-Encoding", c.bodyEncoding)
+	}
+	if c.acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", c.acceptEncoding)
+	}
+
+	// a autenticacao acontece antes do inicio da medicao, a busca de token nao conta como latencia
+	if c.auth != nil {
+		if err := c.auth.apply(req); err != nil {
+			result.Error = err
+			return nil, result
+		}
+	}
+
+	// registra se a requisicao abriu uma conexao nova ou reutilizou uma ociosa, o endereco
+	// conectado e quanto tempo levou o handshake TLS da conexao recebida. GotConn roda na
+	// goroutine da requisicao; os demais hooks rodam na goroutine de discagem, que pode terminar
+	// depois da requisicao quando ela recebe outra conexao, entao seus valores ficam protegidos
+	// pelo mutex. O handshake e gravado na conexao discada e retirado por quem a receber
+	var (
+		mu       sync.Mutex
+		failedIP string
+	)
+	dials := &dialRecord{}
+	trace := &httptrace.ClientTrace{
+		ConnectDone: func(_, addr string, err error) {
+			if err != nil {
+				mu.Lock()
+				failedIP = netutil.HostOf(addr)
+				mu.Unlock()
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			result.ConnReused = info.Reused
+			result.ConnOpened = !info.Reused
+			result.RemoteIP = netutil.HostOf(info.Conn.RemoteAddr().String())
+			result.TLSHandshake = takeHandshake(info.Conn)
+		},
+		TLSHandshakeStart: dials.startHandshake,
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			dials.finishHandshake(err)
+		},
+	}
+	traceCtx := context.WithValue(req.Context(), redirectResultKey{}, result)
+	traceCtx = context.WithValue(traceCtx, dialRecordKey{}, dials)
+	req = req.WithContext(httptrace.WithClientTrace(traceCtx, trace))
+
 	start := time.Now()
 
-	resp, err := c.client.Get(url)
-	duration := time.Since(start)
+	resp, err := c.client.Do(req)
+	result.Duration = time.Since(start)
+
+	mu.Lock()
+	// sem conexao, o erro e atribuido ao endereco cuja discagem falhou
+	if err != nil && result.RemoteIP == "" {
+		result.RemoteIP = failedIP
+	}
+	mu.Unlock()
 
 	if err != nil {
-		return &domain.TestResult{
-			Duration: duration,
-			Error:    err,
-		}, nil
+		result.Error = classifyError(err)
+		return nil, result
 	}
-	defer resp.Body.Close()
 
-	return &domain.TestResult{
-		Duration: duration,
-		Status:   resp.StatusCode,
-	}, nil
+	// respostas geradas pelo proxy nao vem do alvo e contam como erro de proxy; o corpo e
+	// consumido para que a conexao com o proxy possa voltar ao pool
+	if err := forwardProxyError(c.proxy, resp); err != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		result.Error = err
+		return nil, result
+	}
+
+	result.Status = resp.StatusCode
+	if result.InitialStatus == 0 {
+		result.InitialStatus = resp.StatusCode
+	}
+	result.Protocol = resp.Proto
+	result.Attributes = make(map[string]string)
+	if resp.TLS != nil {
+		result.Attributes[domain.AttributeTLS] = tls.VersionName(resp.TLS.Version) + " " + tls.CipherSuiteName(resp.TLS.CipherSuite)
+	}
+	return resp, result
+}
+
+// le o corpo da resposta registrando a codificacao, os bytes recebidos e os descomprimidos
+func readBody(resp *http.Response, result *domain.TestResult, dst io.Writer) error {
+	wire := &countingReader{r: resp.Body}
+	encoding := resp.Header.Get("Content-Encoding")
+	result.Attributes[domain.AttributeContentEncoding] = encoding
+	if encoding == "" {
+		result.Attributes[domain.AttributeContentEncoding] = "identity"
+	}
+
+	decoded, closeDecoder, err := decodeBody(wire, encoding)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrDecode, err)
+	}
+	defer closeDecoder()
+
+	n, err := io.Copy(dst, decoded)
+	result.WireBytes = wire.n
+	result.DecodedBytes = n
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			return classifyError(err)
+		}
+		return fmt.Errorf("%w: %v", domain.ErrDecode, err)
+	}
+	return nil
+}
+
+// envolve o erro do transporte em um erro de dominio para que possa ser agrupado no relatorio
+func classifyError(err error) error {
+	if isProxyError(err) {
+		if errors.Is(err, domain.ErrProxy) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", domain.ErrProxy, err)
+	}
+	return netutil.ClassifyError(err)
 }
@@ -0,0 +1,90 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"strings"
+)
+
+// valor padrao de Accept-Encoding, o mesmo que o transporte do Go envia
+const defaultAcceptEncoding = "gzip"
+
+// comprime o corpo da requisicao uma unica vez, antes do teste, no formato informado
+func compressBody(body []byte, encoding string) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+
+	switch encoding {
+	case "":
+		return body, nil
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	case "zstd":
+		enc, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, err
+		}
+		w = enc
+	default:
+		return nil, fmt.Errorf("unsupported body encoding %q, expected gzip, br or zstd", encoding)
+	}
+
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// valor do cabecalho Accept-Encoding; vazio usa o padrao e "none" nao envia o cabecalho
+func acceptEncodingHeader(value string) string {
+	switch value {
+	case "":
+		return defaultAcceptEncoding
+	case "none":
+		return ""
+	}
+	return value
+}
+
+// retorna um leitor com o conteudo descomprimido conforme o Content-Encoding da resposta;
+// codificacoes desconhecidas sao lidas como estao
+func decodeBody(r io.Reader, encoding string) (io.Reader, func(), error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gz, func() { gz.Close() }, nil
+	case "br":
+		return brotli.NewReader(r), func() {}, nil
+	case "zstd":
+		dec, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dec, dec.Close, nil
+	}
+	return r, func() {}, nil
+}
+
+// conta os bytes lidos do corpo como recebidos na conexao, antes da descompressao
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
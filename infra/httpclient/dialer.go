@@ -0,0 +1,123 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// dialer que conecta em unix domain sockets, aplica os overrides de DNS (--resolve) e,
+// opcionalmente, distribui as conexoes entre todos os enderecos A/AAAA do host
+type dialer struct {
+	net       *net.Dialer
+	overrides map[string]string // host:port -> ip
+	spread    bool
+
+	mu       sync.Mutex
+	resolved map[string][]net.IP // host -> enderecos resolvidos
+	next     map[string]int      // host -> proximo indice do round-robin
+}
+
+func newDialer(netDialer *net.Dialer, resolve []string, spread bool) (*dialer, error) {
+	overrides := make(map[string]string, len(resolve))
+	for _, entry := range resolve {
+		hostPort, ip, err := parseResolve(entry)
+		if err != nil {
+			return nil, err
+		}
+		overrides[hostPort] = ip
+	}
+
+	return &dialer{
+		net:       netDialer,
+		overrides: overrides,
+		spread:    spread,
+		resolved:  make(map[string][]net.IP),
+		next:      make(map[string]int),
+	}, nil
+}
+
+// interpreta uma entrada no formato do curl: host:port:ip (o ip pode vir entre colchetes)
+func parseResolve(entry string) (hostPort, ip string, err error) {
+	parts := strings.SplitN(entry, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid resolve entry %q, expected host:port:ip", entry)
+	}
+
+	ip = strings.TrimSuffix(strings.TrimPrefix(parts[2], "["), "]")
+	if net.ParseIP(ip) == nil {
+		return "", "", fmt.Errorf("invalid ip %q in resolve entry %q", ip, entry)
+	}
+
+	return net.JoinHostPort(parts[0], parts[1]), ip, nil
+}
+
+// conecta ao endereco e registra a conexao na requisicao que disparou a discagem, para que o
+// handshake TLS feito sobre ela seja atribuido a conexao
+func (d *dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := d.dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	dialed := &dialedConn{Conn: conn}
+	recordDial(ctx, dialed)
+	return dialed, nil
+}
+
+func (d *dialer) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	if socketPath, ok := ctx.Value(socketPathKey{}).(string); ok {
+		return d.net.DialContext(ctx, "unix", socketPath)
+	}
+
+	if ip, ok := d.overrides[addr]; ok {
+		_, port, _ := net.SplitHostPort(addr)
+		return d.net.DialContext(ctx, network, net.JoinHostPort(ip, port))
+	}
+
+	if d.spread {
+		host, port, err := net.SplitHostPort(addr)
+		if err == nil && net.ParseIP(host) == nil {
+			ip, err := d.nextIP(ctx, host)
+			if err != nil {
+				return nil, err
+			}
+			return d.net.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		}
+	}
+
+	return d.net.DialContext(ctx, network, addr)
+}
+
+// escolhe o proximo endereco do host em round-robin, resolvendo o DNS apenas uma vez
+func (d *dialer) nextIP(ctx context.Context, host string) (net.IP, error) {
+	d.mu.Lock()
+	ips, ok := d.resolved[host]
+	d.mu.Unlock()
+
+	if !ok {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		ips = make([]net.IP, 0, len(addrs))
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+		if len(ips) == 0 {
+			return nil, &net.DNSError{Err: "no addresses found", Name: host, IsNotFound: true}
+		}
+
+		d.mu.Lock()
+		d.resolved[host] = ips
+		d.mu.Unlock()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	i := d.next[host] % len(ips)
+	d.next[host]++
+	return ips[i], nil
+}
@@ -0,0 +1,59 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// requisicao GraphQL enviada como POST application/json; habilitada quando Query e informada
+type GraphQLOptions struct {
+	Query         string          // documento da query ou mutation
+	Variables     json.RawMessage // objeto JSON com as variaveis da operacao
+	OperationName string          // operacao executada quando o documento define mais de uma
+}
+
+func (o GraphQLOptions) enabled() bool {
+	return o.Query != ""
+}
+
+// monta o corpo da requisicao no formato esperado pelos servidores GraphQL
+func (o GraphQLOptions) body() ([]byte, error) {
+	payload := struct {
+		Query         string          `json:"query"`
+		Variables     json.RawMessage `json:"variables,omitempty"`
+		OperationName string          `json:"operationName,omitempty"`
+	}{o.Query, o.Variables, o.OperationName}
+
+	if len(o.Variables) > 0 {
+		var vars map[string]any
+		if err := json.Unmarshal(o.Variables, &vars); err != nil {
+			return nil, errors.New("graphql variables must be a JSON object")
+		}
+	}
+	return json.Marshal(payload)
+}
+
+type graphQLResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// retorna as mensagens do array errors da resposta; respostas que nao sao JSON nao tem
+// erros GraphQL e sao avaliadas apenas pelo status HTTP
+func graphQLErrors(body []byte) []string {
+	var resp graphQLResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil
+	}
+
+	messages := make([]string, 0, len(resp.Errors))
+	for _, e := range resp.Errors {
+		message := e.Message
+		if message == "" {
+			message = "unknown error"
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
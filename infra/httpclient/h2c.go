@@ -0,0 +1,123 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"golang.org/x/net/http2"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// intervalo sem trafego apos o qual a conexao h2c e verificada com um ping, equivalente ao
+// keep-alive TCP do dialer
+const h2cReadIdleTimeout = 30 * time.Second
+
+// tempo maximo de espera pela resposta do ping antes de descartar a conexao
+const h2cPingTimeout = 15 * time.Second
+
+// rejeita opcoes que nao se aplicam ao h2c, em vez de ignora-las silenciosamente
+func (o Options) validateH2C() error {
+	if !o.H2C {
+		return nil
+	}
+	if o.Proxy != "" {
+		return errors.New("h2c does not support proxies")
+	}
+	if o.HTTP2 {
+		return errors.New("h2c and http2 cannot be combined")
+	}
+	if !o.TLS.isZero() {
+		return errors.New("h2c does not use TLS; TLS options are not supported")
+	}
+	return nil
+}
+
+// transporte HTTP/2 em texto puro: a conexao TCP e usada diretamente, sem negociacao TLS.
+// As opcoes de HTTP/1 sao mapeadas para os equivalentes do http2.Transport
+func newH2CTransport(dialer *dialer, opts Options) http.RoundTripper {
+	transport := &http2.Transport{
+		AllowHTTP:          true,
+		DisableCompression: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		},
+		IdleConnTimeout: 90 * time.Second,
+		// quando o servidor limita os streams simultaneos, novas conexoes sao abertas em vez de
+		// enfileirar as requisicoes, assim como o pool do HTTP/1 abre uma conexao por worker
+		StrictMaxConcurrentStreams: false,
+	}
+	if !opts.DisableKeepAlives {
+		transport.ReadIdleTimeout = h2cReadIdleTimeout
+		transport.PingTimeout = h2cPingTimeout
+	}
+
+	return &h2cTransport{
+		transport:         transport,
+		headerTimeout:     opts.ResponseHeaderTimeout,
+		disableKeepAlives: opts.DisableKeepAlives,
+	}
+}
+
+// aplica sobre o http2.Transport o timeout de cabecalhos e o fechamento da conexao por
+// requisicao, que o http2 nao oferece como opcoes do transporte
+type h2cTransport struct {
+	transport         *http2.Transport
+	headerTimeout     time.Duration
+	disableKeepAlives bool
+}
+
+func (t *h2cTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.disableKeepAlives {
+		req = req.Clone(req.Context())
+		req.Close = true
+	}
+	if t.headerTimeout <= 0 {
+		return t.transport.RoundTrip(req)
+	}
+
+	// o contexto cancelado pelo timer so e liberado quando o corpo da resposta e fechado
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(t.headerTimeout, cancel)
+
+	resp, err := t.transport.RoundTrip(req.WithContext(ctx))
+	if !timer.Stop() {
+		cancel()
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, errHeaderTimeout
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (t *h2cTransport) CloseIdleConnections() {
+	t.transport.CloseIdleConnections()
+}
+
+// erro de timeout equivalente ao do http.Transport, classificado como domain.ErrTimeout
+var errHeaderTimeout error = headerTimeoutError{}
+
+type headerTimeoutError struct{}
+
+func (headerTimeoutError) Error() string   { return "http2: timeout awaiting response headers" }
+func (headerTimeoutError) Timeout() bool   { return true }
+func (headerTimeoutError) Temporary() bool { return true }
+
+// libera o contexto da requisicao quando o corpo da resposta e fechado
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
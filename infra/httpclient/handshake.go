@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// conexao aberta pelo dialer; guarda a duracao do handshake TLS feito sobre ela. O handshake
+// roda na goroutine de discagem com o trace da requisicao que disparou a discagem, mas o pool
+// (principalmente no HTTP/2) pode entregar a conexao a outra requisicao, entao a duracao fica
+// na conexao e e retirada pela primeira requisicao que a recebe
+type dialedConn struct {
+	net.Conn
+	handshake atomic.Int64
+}
+
+// retorna a duracao do handshake apenas para a primeira requisicao que recebe a conexao
+func (c *dialedConn) takeHandshake() time.Duration {
+	return time.Duration(c.handshake.Swap(0))
+}
+
+// ultima conexao discada para uma requisicao, usada pelos hooks de TLS do trace, que nao
+// recebem a conexao
+type dialRecord struct {
+	mu             sync.Mutex
+	conn           *dialedConn
+	handshakeStart time.Time
+}
+
+type dialRecordKey struct{}
+
+// registra a conexao discada com o contexto de uma requisicao que leva um dialRecord
+func recordDial(ctx context.Context, conn *dialedConn) {
+	record, ok := ctx.Value(dialRecordKey{}).(*dialRecord)
+	if !ok {
+		return
+	}
+	record.mu.Lock()
+	record.conn = conn
+	record.mu.Unlock()
+}
+
+func (r *dialRecord) startHandshake() {
+	r.mu.Lock()
+	r.handshakeStart = time.Now()
+	r.mu.Unlock()
+}
+
+// grava a duracao na conexao discada; em proxies https o handshake com o alvo, feito por
+// ultimo, substitui o handshake com o proxy
+func (r *dialRecord) finishHandshake(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil || r.conn == nil || r.handshakeStart.IsZero() {
+		return
+	}
+	r.conn.handshake.Store(int64(time.Since(r.handshakeStart)))
+}
+
+// retira o handshake da conexao entregue pelo pool, atravessando as camadas TLS ate a conexao
+// aberta pelo dialer
+func takeHandshake(conn net.Conn) time.Duration {
+	for {
+		switch c := conn.(type) {
+		case *tls.Conn:
+			conn = c.NetConn()
+		case *dialedConn:
+			return c.takeHandshake()
+		default:
+			return 0
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-expert-stress-test/domain"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// resolve a funcao de proxy do transporte: a URL informada ou, quando vazia, as variaveis
+// HTTPS_PROXY, HTTP_PROXY e NO_PROXY do ambiente
+func proxyFunc(proxyURL, proxyUser string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		if proxyUser != "" {
+			return nil, errors.New("proxy credentials require a proxy url")
+		}
+		return http.ProxyFromEnvironment, nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+
+	if proxyUser != "" {
+		username, password, _ := strings.Cut(proxyUser, ":")
+		u.User = url.UserPassword(username, password)
+	}
+
+	return http.ProxyURL(u), nil
+}
+
+// transforma uma resposta diferente de 200 ao CONNECT em um erro de proxy
+func checkProxyConnect(_ context.Context, _ *url.URL, _ *http.Request, resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: CONNECT returned %s", domain.ErrProxy, resp.Status)
+	}
+	return nil
+}
+
+// indica se o erro aconteceu ao conectar ou negociar com o proxy, e nao com o alvo
+func isProxyError(err error) bool {
+	if errors.Is(err, domain.ErrProxy) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && (opErr.Op == "proxyconnect" || strings.HasPrefix(opErr.Op, "socks"))
+}
+
+// em alvos http:// o proxy encaminha a requisicao e responde no lugar do alvo, entao suas falhas
+// chegam como respostas: 407, respostas com erro em Proxy-Status (RFC 9209) e 502/504 sem o
+// cabecalho Via que o proxy adiciona as respostas encaminhadas do alvo
+func forwardProxyError(proxy func(*http.Request) (*url.URL, error), resp *http.Response) error {
+	if proxy == nil || resp.Request == nil || resp.Request.URL.Scheme != "http" {
+		return nil
+	}
+	proxyURL, err := proxy(resp.Request)
+	if err != nil || proxyURL == nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") {
+		return nil
+	}
+
+	gatewayError := resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusGatewayTimeout
+	switch {
+	case resp.StatusCode == http.StatusProxyAuthRequired:
+	case strings.Contains(resp.Header.Get("Proxy-Status"), "error="):
+	case gatewayError && resp.Header.Get("Via") == "":
+	default:
+		return nil
+	}
+	return fmt.Errorf("%w: proxy returned %s", domain.ErrProxy, resp.Status)
+}
@@ -0,0 +1,45 @@
+package httpclient
+
+import (
+	"errors"
+	"go-expert-stress-test/domain"
+	"net/http"
+)
+
+// limite de redirects quando nenhum e configurado, o mesmo do http.Client
+const defaultMaxRedirects = 10
+
+// chave do contexto que aponta para o resultado da requisicao em andamento, usada para
+// registrar a cadeia de redirects
+type redirectResultKey struct{}
+
+// politica de redirects: segue ate maxRedirects saltos e, ao atingir o limite, retorna a
+// ultima resposta (3xx) em vez de um erro, para que loops aparecam na distribuicao de status
+func checkRedirect(maxRedirects int) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		result, _ := req.Context().Value(redirectResultKey{}).(*domain.TestResult)
+		if result != nil && result.InitialStatus == 0 && req.Response != nil {
+			result.InitialStatus = req.Response.StatusCode
+		}
+
+		if len(via) > maxRedirects {
+			return http.ErrUseLastResponse
+		}
+
+		if result != nil {
+			result.Redirects = len(via)
+		}
+		return nil
+	}
+}
+
+// converte o valor de Options.MaxRedirects: nil usa o padrao e zero nao segue redirects
+func redirectLimit(maxRedirects *int) (int, error) {
+	if maxRedirects == nil {
+		return defaultMaxRedirects, nil
+	}
+	if *maxRedirects < 0 {
+		return 0, errors.New("max redirects must not be negative")
+	}
+	return *maxRedirects, nil
+}
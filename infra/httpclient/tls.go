@@ -0,0 +1,101 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// configuracao TLS do cliente; campos vazios mantem o comportamento padrao do Go
+type TLSOptions struct {
+	CertFile   string   // certificado do cliente (PEM) para mTLS
+	KeyFile    string   // chave privada do certificado do cliente (PEM)
+	CAFile     string   // bundle de CAs (PEM) usado para validar o servidor
+	ServerName string   // sobrescreve o SNI e o nome validado no certificado
+	MinVersion string   // versao minima: 1.0, 1.1, 1.2 ou 1.3
+	Ciphers    []string // cipher suites permitidas (apenas TLS 1.0-1.2), por nome
+	Insecure   bool     // nao valida o certificado do servidor
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// indica que nenhuma opcao de TLS foi informada
+func (o TLSOptions) isZero() bool {
+	return o.CertFile == "" && o.KeyFile == "" && o.CAFile == "" && o.ServerName == "" &&
+		o.MinVersion == "" && len(o.Ciphers) == 0 && !o.Insecure
+}
+
+// monta o tls.Config a partir das opcoes, carregando certificados e CAs do disco
+func (o TLSOptions) config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.Insecure,
+	}
+
+	if o.CertFile != "" || o.KeyFile != "" {
+		if o.CertFile == "" || o.KeyFile == "" {
+			return nil, errors.New("client certificate and key must be provided together")
+		}
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if o.MinVersion != "" {
+		version, ok := tlsVersions[o.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS version %q", o.MinVersion)
+		}
+		cfg.MinVersion = version
+	}
+
+	if len(o.Ciphers) > 0 {
+		suites, err := cipherSuiteIDs(o.Ciphers)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CipherSuites = suites
+	}
+
+	return cfg, nil
+}
+
+// converte nomes de cipher suites (ex: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256) para seus IDs
+func cipherSuiteIDs(names []string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// prefixo dos alvos servidos por unix domain socket, ex: unix:///var/run/app.sock:/health
+const unixScheme = "unix://"
+
+// chave de contexto com o caminho do socket que o dialer deve usar
+type socketPathKey struct{}
+
+// monta a requisicao para o alvo; alvos unix:// viram uma URL HTTP comum e o caminho do
+// socket segue no contexto ate o dialer
+func newRequest(ctx context.Context, method, target string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	if !strings.HasPrefix(target, unixScheme) {
+		return http.NewRequestWithContext(ctx, method, target, reader)
+	}
+
+	socketPath, path := splitUnixTarget(target)
+	return http.NewRequestWithContext(context.WithValue(ctx, socketPathKey{}, socketPath), method, "http://unix"+path, reader)
+}
+
+// separa unix:///var/run/app.sock:/path em caminho do socket e path HTTP (padrao /)
+func splitUnixTarget(target string) (socketPath, path string) {
+	rest := strings.TrimPrefix(target, unixScheme)
+	socketPath, path, found := strings.Cut(rest, ":")
+	if !found || path == "" {
+		path = "/"
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return socketPath, path
+}
+
+// alvos unix:// nunca passam pelo proxy, mesmo com HTTP_PROXY definido no ambiente
+func bypassProxyForUnix(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if _, ok := req.Context().Value(socketPathKey{}).(string); ok {
+			return nil, nil
+		}
+		return proxy(req)
+	}
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"fmt"
+	"go-expert-stress-test/domain"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// limites (em segundos) dos buckets do histograma de latencia
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// acumula as metricas do teste e as expoe no formato texto do Prometheus
+type PrometheusCollector struct {
+	mu               sync.Mutex
+	requestsByStatus map[int]uint64
+	errorsByClass    map[string]uint64
+	bucketCounts     []uint64
+	latencySum       float64
+	latencyCount     uint64
+	activeWorkers    int
+	targetRPS        float64
+}
+
+func NewPrometheusCollector() *PrometheusCollector {
+	return &PrometheusCollector{
+		requestsByStatus: make(map[int]uint64),
+		errorsByClass:    make(map[string]uint64),
+		bucketCounts:     make([]uint64, len(latencyBuckets)),
+	}
+}
+
+// acompanha os eventos do teste atualizando contadores, histograma e gauges
+func (c *PrometheusCollector) OnEvent(event domain.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch event.Type {
+	case domain.EventStarted:
+		c.targetRPS = float64(event.Config.Rate)
+	case domain.EventWorkerStarted:
+		c.activeWorkers++
+	case domain.EventWorkerFinished:
+		c.activeWorkers--
+	case domain.EventRequestDone:
+		c.observe(*event.Result)
+	}
+}
+
+// contabiliza um resultado nos contadores e no histograma de latencia
+func (c *PrometheusCollector) observe(result domain.TestResult) {
+	if result.Error != nil {
+		c.errorsByClass[domain.ErrorClass(result.Error)]++
+	} else {
+		c.requestsByStatus[result.Status]++
+	}
+
+	seconds := result.Duration.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			c.bucketCounts[i]++
+		}
+	}
+	c.latencySum += seconds
+	c.latencyCount++
+}
+
+// escreve todas as metricas no formato de exposicao texto do Prometheus
+func (c *PrometheusCollector) WriteMetrics(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pw := &printer{w: w}
+
+	pw.printf("# HELP stress_requests_total Requisicoes concluidas por status HTTP.\n")
+	pw.printf("# TYPE stress_requests_total counter\n")
+	statuses := make([]int, 0, len(c.requestsByStatus))
+	for status := range c.requestsByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		pw.printf("stress_requests_total{status=\"%d\"} %d\n", status, c.requestsByStatus[status])
+	}
+
+	pw.printf("# HELP stress_errors_total Requisicoes que falharam por classe de erro.\n")
+	pw.printf("# TYPE stress_errors_total counter\n")
+	classes := make([]string, 0, len(c.errorsByClass))
+	for class := range c.errorsByClass {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		pw.printf("stress_errors_total{class=%q} %d\n", class, c.errorsByClass[class])
+	}
+
+	pw.printf("# HELP stress_request_duration_seconds Latencia das requisicoes.\n")
+	pw.printf("# TYPE stress_request_duration_seconds histogram\n")
+	for i, bound := range latencyBuckets {
+		pw.printf("stress_request_duration_seconds_bucket{le=%q} %d\n", formatFloat(bound), c.bucketCounts[i])
+	}
+	pw.printf("stress_request_duration_seconds_bucket{le=\"+Inf\"} %d\n", c.latencyCount)
+	pw.printf("stress_request_duration_seconds_sum %s\n", formatFloat(c.latencySum))
+	pw.printf("stress_request_duration_seconds_count %d\n", c.latencyCount)
+
+	pw.printf("# HELP stress_active_workers Workers executando requisicoes.\n")
+	pw.printf("# TYPE stress_active_workers gauge\n")
+	pw.printf("stress_active_workers %d\n", c.activeWorkers)
+
+	pw.printf("# HELP stress_target_rps Taxa alvo de requisicoes por segundo (0 = sem limite).\n")
+	pw.printf("# TYPE stress_target_rps gauge\n")
+	pw.printf("stress_target_rps %s\n", formatFloat(c.targetRPS))
+
+	return pw.err
+}
+
+func (c *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := c.WriteMetrics(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// guarda o primeiro erro de escrita para nao precisar checar a cada linha
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,15 @@
+package netutil
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// interrompe a escrita e a leitura em andamento em conn quando ctx e cancelado, vencendo o
+// prazo da conexao. A funcao retornada encerra o monitoramento e indica se o cancelamento
+// chegou a interromper a conexao; com o prazo ja vencido, ela nao pode ser reutilizada
+func CancelOnDone(ctx context.Context, conn net.Conn) func() bool {
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	return func() bool { return !stop() }
+}
@@ -0,0 +1,68 @@
+package netutil
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"go-expert-stress-test/domain"
+	"net"
+)
+
+// envolve o erro de rede em um erro de dominio para que possa ser agrupado no relatorio;
+// compartilhado pelos adaptadores de todos os protocolos
+func ClassifyError(err error) error {
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr):
+		return fmt.Errorf("%w: %v", domain.ErrDNS, err)
+	case IsTimeout(err):
+		return fmt.Errorf("%w: %v", domain.ErrTimeout, err)
+	case IsTLSError(err):
+		return fmt.Errorf("%w: %v", domain.ErrTLS, err)
+	}
+	return fmt.Errorf("%w: %v", domain.ErrConnection, err)
+}
+
+// indica se o erro e um timeout da conexao ou do contexto
+func IsTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// indica se o erro aconteceu durante o handshake ou a validacao do certificado
+func IsTLSError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var echErr *tls.ECHRejectionError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+
+	return errors.As(err, &certErr) ||
+		errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &echErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr) ||
+		isTLSAlert(err)
+}
+
+// o crypto/tls entrega os alertas enviados ou recebidos no handshake (ex: certificado de cliente
+// ausente, nenhuma cipher suite em comum) como net.OpError com as operacoes abaixo
+func isTLSAlert(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && (opErr.Op == "remote error" || opErr.Op == "local error")
+}
+
+// extrai o host de um endereco host:port
+func HostOf(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
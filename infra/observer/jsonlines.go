@@ -0,0 +1,98 @@
+package observer
+
+import (
+	"encoding/json"
+	"go-expert-stress-test/domain"
+	"io"
+	"sync"
+	"time"
+)
+
+// escreve cada evento como uma linha JSON, para consumo por outras ferramentas
+type JSONLines struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+type jsonEvent struct {
+	Type        domain.EventType `json:"type"`
+	Time        time.Time        `json:"time"`
+	URL         string           `json:"url,omitempty"`
+	Requests    int              `json:"requests,omitempty"`
+	Concurrency int              `json:"concurrency,omitempty"`
+	Worker      *int             `json:"worker,omitempty"`
+	Status      int              `json:"status,omitempty"`
+	Protocol    string           `json:"protocol,omitempty"`
+	DurationMs  float64          `json:"duration_ms,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	ErrorClass  string           `json:"error_class,omitempty"`
+	Stage       string           `json:"stage,omitempty"`
+	Success     int              `json:"success,omitempty"`
+	Errors      int              `json:"errors,omitempty"`
+	Level       int              `json:"level,omitempty"`
+	Throughput  float64          `json:"throughput,omitempty"`
+	P99Ms       float64          `json:"p99_ms,omitempty"`
+	ErrorRate   float64          `json:"error_rate,omitempty"`
+	Breach      string           `json:"breach,omitempty"`
+}
+
+func NewJSONLines(w io.Writer) *JSONLines {
+	return &JSONLines{enc: json.NewEncoder(w)}
+}
+
+func (j *JSONLines) OnEvent(event domain.Event) {
+	out := jsonEvent{
+		Type:  event.Type,
+		Time:  event.Time,
+		Stage: event.Stage,
+	}
+
+	switch event.Type {
+	case domain.EventStarted:
+		out.URL = event.Config.URL
+		out.Requests = event.Config.Requests
+		out.Concurrency = event.Config.Concurrency
+	case domain.EventWorkerStarted, domain.EventWorkerFinished:
+		out.Worker = &event.WorkerID
+	case domain.EventRequestDone:
+		out.Worker = &event.WorkerID
+		out.Status = event.Result.Status
+		out.Protocol = event.Result.Protocol
+		out.DurationMs = durationMs(event.Result.Duration)
+		if event.Result.Error != nil {
+			out.Error = event.Result.Error.Error()
+			out.ErrorClass = domain.ErrorClass(event.Result.Error)
+		}
+	case domain.EventFinished:
+		out.Requests = event.Report.TotalRequests
+		out.Success = event.Report.SuccessRequests
+		out.Errors = event.Report.ErrorCount
+		out.DurationMs = durationMs(event.Report.TotalDuration)
+	case domain.EventStepDone:
+		out.Level = event.Step.Level
+		out.Throughput = event.Step.Throughput
+		out.P99Ms = durationMs(event.Step.P99)
+		out.ErrorRate = event.Step.ErrorRate
+		out.Breach = event.Step.Breach
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	// apos a primeira falha de escrita os eventos seguintes sao descartados
+	if j.err != nil {
+		return
+	}
+	j.err = j.enc.Encode(out)
+}
+
+// retorna o primeiro erro de escrita, ou nil quando todos os eventos foram gravados
+func (j *JSONLines) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,33 @@
+package observer
+
+import (
+	"go-expert-stress-test/domain"
+)
+
+// observer que descarta todos os eventos
+type Noop struct{}
+
+func NewNoop() Noop {
+	return Noop{}
+}
+
+func (Noop) OnEvent(event domain.Event) {}
+
+// repassa cada evento para varios observers, na ordem em que foram informados
+type Multi []domain.ProgressObserver
+
+func NewMulti(observers ...domain.ProgressObserver) Multi {
+	multi := make(Multi, 0, len(observers))
+	for _, o := range observers {
+		if o != nil {
+			multi = append(multi, o)
+		}
+	}
+	return multi
+}
+
+func (m Multi) OnEvent(event domain.Event) {
+	for _, o := range m {
+		o.OnEvent(event)
+	}
+}
@@ -0,0 +1,291 @@
+package progress
+
+import (
+	"fmt"
+	"go-expert-stress-test/domain"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// janela usada para calcular as metricas moveis do dashboard
+const dashboardWindow = 10 * time.Second
+
+type sample struct {
+	at       time.Time
+	duration time.Duration
+	failed   bool
+}
+
+// painel agregado que substitui as barras por worker, atualizado a cada segundo
+type Dashboard struct {
+	out           io.Writer
+	totalRequests int
+	mu            sync.Mutex
+	samples       []sample
+	statusCounts  map[int]int
+	errorCount    int
+	completed     int
+	activeWorkers int
+	targetRPS     float64
+	startTime     time.Time
+	renderedLines int
+	stop          chan struct{} // nil quando nao ha teste em andamento
+	stopped       chan struct{}
+}
+
+// metricas agregadas exibidas pelo dashboard; RPS, latencias e taxa de erros consideram apenas a
+// janela movel
+type DashboardStats struct {
+	Elapsed       time.Duration
+	Remaining     time.Duration // estimativa pelo RPS atual, zero enquanto nao ha vazao
+	Completed     int
+	TotalRequests int
+	ActiveWorkers int
+	RPS           float64
+	TargetRPS     float64
+	ErrorRate     float64 // percentual de requisicoes sem sucesso (erros e respostas fora de 2xx)
+	P50           time.Duration
+	P95           time.Duration
+	P99           time.Duration
+	StatusCounts  map[int]int
+	Errors        int
+}
+
+// cria um dashboard que passa a ser desenhado em out quando o teste inicia
+func NewDashboard(out io.Writer) *Dashboard {
+	return &Dashboard{
+		out:          out,
+		statusCounts: make(map[int]int),
+	}
+}
+
+func (d *Dashboard) OnEvent(event domain.Event) {
+	switch event.Type {
+	case domain.EventStarted:
+		d.start(event.Config.Requests, float64(event.Config.Rate))
+	case domain.EventWorkerStarted:
+		d.mu.Lock()
+		d.activeWorkers++
+		d.mu.Unlock()
+	case domain.EventWorkerFinished:
+		d.mu.Lock()
+		d.activeWorkers--
+		d.mu.Unlock()
+	case domain.EventRequestDone:
+		d.observe(*event.Result)
+	case domain.EventFinished:
+		d.Stop()
+	}
+}
+
+// zera as metricas a cada teste; o painel de uma execucao seguinte e desenhado abaixo do anterior
+func (d *Dashboard) start(totalRequests int, targetRPS float64) {
+	d.Stop()
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	d.mu.Lock()
+	d.totalRequests = totalRequests
+	d.targetRPS = targetRPS
+	d.startTime = time.Now()
+	d.samples = nil
+	d.statusCounts = make(map[int]int)
+	d.errorCount = 0
+	d.completed = 0
+	d.activeWorkers = 0
+	d.renderedLines = 0
+	d.stop = stop
+	d.stopped = stopped
+	d.mu.Unlock()
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				d.render()
+			case <-stop:
+				d.render()
+				return
+			}
+		}
+	}()
+}
+
+// interrompe a atualizacao periodica apos uma ultima renderizacao
+func (d *Dashboard) Stop() {
+	d.mu.Lock()
+	stop, stopped := d.stop, d.stopped
+	d.stop = nil
+	d.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-stopped
+}
+
+// contabiliza uma requisicao; o detalhe por worker e agregado
+func (d *Dashboard) observe(result domain.TestResult) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.completed++
+	if result.Error != nil {
+		d.errorCount++
+	} else {
+		d.statusCounts[result.Status]++
+	}
+
+	d.samples = append(d.samples, sample{
+		at:       time.Now(),
+		duration: result.Duration,
+		failed:   !result.Succeeded(),
+	})
+}
+
+// agrega as amostras da janela movel que termina em now
+func (d *Dashboard) Stats(now time.Time) DashboardStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats(now)
+}
+
+func (d *Dashboard) stats(now time.Time) DashboardStats {
+	d.discardOldSamples(now)
+
+	stats := DashboardStats{
+		Elapsed:       now.Sub(d.startTime),
+		Completed:     d.completed,
+		TotalRequests: d.totalRequests,
+		ActiveWorkers: d.activeWorkers,
+		TargetRPS:     d.targetRPS,
+		StatusCounts:  make(map[int]int, len(d.statusCounts)),
+		Errors:        d.errorCount,
+	}
+	for status, count := range d.statusCounts {
+		stats.StatusCounts[status] = count
+	}
+
+	window := min(stats.Elapsed, dashboardWindow)
+
+	durations := make([]time.Duration, 0, len(d.samples))
+	failed := 0
+	for _, s := range d.samples {
+		durations = append(durations, s.duration)
+		if s.failed {
+			failed++
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	stats.P50 = domain.Percentile(durations, 50)
+	stats.P95 = domain.Percentile(durations, 95)
+	stats.P99 = domain.Percentile(durations, 99)
+
+	if window > 0 {
+		stats.RPS = float64(len(d.samples)) / window.Seconds()
+	}
+	if len(d.samples) > 0 {
+		stats.ErrorRate = float64(failed) / float64(len(d.samples)) * 100
+	}
+	if stats.RPS > 0 {
+		stats.Remaining = time.Duration(float64(d.totalRequests-d.completed) / stats.RPS * float64(time.Second))
+	}
+	return stats
+}
+
+// redesenha o painel no lugar do anterior
+func (d *Dashboard) render() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := d.stats(time.Now())
+
+	remaining := "--"
+	if stats.RPS > 0 {
+		remaining = formatClock(stats.Remaining)
+	}
+
+	progress := 0.0
+	if stats.TotalRequests > 0 {
+		progress = float64(stats.Completed) / float64(stats.TotalRequests)
+	}
+	barWidth := 40
+	filled := int(progress * float64(barWidth))
+
+	target := "sem limite"
+	if stats.TargetRPS > 0 {
+		target = fmt.Sprintf("%.0f", stats.TargetRPS)
+	}
+
+	lines := []string{
+		"Stress Tester - Dashboard",
+		fmt.Sprintf("Tempo: %s decorrido | ~%s restante", formatClock(stats.Elapsed), remaining),
+		fmt.Sprintf("Progresso: [%s%s] %d/%d (%.1f%%)",
+			strings.Repeat("█", filled),
+			strings.Repeat("░", barWidth-filled),
+			stats.Completed,
+			stats.TotalRequests,
+			progress*100),
+		fmt.Sprintf("Workers ativos: %d | RPS: %.1f (alvo: %s) | Erros: %.1f%%",
+			stats.ActiveWorkers, stats.RPS, target, stats.ErrorRate),
+		fmt.Sprintf("Latência: p50 %v | p95 %v | p99 %v",
+			stats.P50.Round(time.Millisecond),
+			stats.P95.Round(time.Millisecond),
+			stats.P99.Round(time.Millisecond)),
+		"Status: " + d.formatStatusCounts(),
+	}
+
+	// volta o cursor para o inicio do painel anterior antes de reescrever
+	if d.renderedLines > 0 {
+		fmt.Fprintf(d.out, "\033[%dA", d.renderedLines)
+	}
+	for _, line := range lines {
+		fmt.Fprintf(d.out, "\r\033[K%s\n", line)
+	}
+	d.renderedLines = len(lines)
+}
+
+// descarta amostras fora da janela movel
+func (d *Dashboard) discardOldSamples(now time.Time) {
+	cutoff := now.Add(-dashboardWindow)
+	i := 0
+	for i < len(d.samples) && d.samples[i].at.Before(cutoff) {
+		i++
+	}
+	d.samples = d.samples[i:]
+}
+
+func (d *Dashboard) formatStatusCounts() string {
+	statuses := make([]int, 0, len(d.statusCounts))
+	for status := range d.statusCounts {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	parts := make([]string, 0, len(statuses)+1)
+	for _, status := range statuses {
+		parts = append(parts, fmt.Sprintf("%d: %d", status, d.statusCounts[status]))
+	}
+	if d.errorCount > 0 {
+		parts = append(parts, fmt.Sprintf("erros: %d", d.errorCount))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " | ")
+}
+
+func formatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
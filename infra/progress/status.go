@@ -0,0 +1,111 @@
+package progress
+
+import (
+	"fmt"
+	"go-expert-stress-test/domain"
+	"io"
+	"sync"
+	"time"
+)
+
+// imprime linhas de status em texto puro periodicamente, para saidas que nao sao terminais (ex: logs de CI)
+type StatusLine struct {
+	out           io.Writer
+	totalRequests int
+	interval      time.Duration
+	mu            sync.Mutex
+	completed     int
+	startTime     time.Time
+	stop          chan struct{} // nil quando nao ha teste em andamento
+	stopped       chan struct{}
+}
+
+// cria um StatusLine que escreve em out a cada intervalo
+func NewStatusLine(out io.Writer, interval time.Duration) *StatusLine {
+	return &StatusLine{
+		out:      out,
+		interval: interval,
+	}
+}
+
+func (s *StatusLine) OnEvent(event domain.Event) {
+	switch event.Type {
+	case domain.EventStarted:
+		s.start(event.Config.Requests)
+	case domain.EventRequestDone:
+		s.mu.Lock()
+		s.completed++
+		s.mu.Unlock()
+	case domain.EventFinished:
+		s.Stop()
+	}
+}
+
+// reinicia a contagem a cada teste, permitindo reutilizar o StatusLine em execucoes seguidas
+func (s *StatusLine) start(totalRequests int) {
+	s.Stop()
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	s.mu.Lock()
+	s.totalRequests = totalRequests
+	s.completed = 0
+	s.startTime = time.Now()
+	s.stop = stop
+	s.stopped = stopped
+	s.mu.Unlock()
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.print()
+			case <-stop:
+				s.print()
+				return
+			}
+		}
+	}()
+}
+
+// interrompe as linhas periodicas apos imprimir o status final
+func (s *StatusLine) Stop() {
+	s.mu.Lock()
+	stop, stopped := s.stop, s.stopped
+	s.stop = nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-stopped
+}
+
+func (s *StatusLine) print() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := time.Since(s.startTime)
+	progress := 0.0
+	if s.totalRequests > 0 {
+		progress = float64(s.completed) / float64(s.totalRequests) * 100
+	}
+	rps := 0.0
+	if elapsed > 0 {
+		rps = float64(s.completed) / elapsed.Seconds()
+	}
+
+	fmt.Fprintf(s.out, "[%s] %d/%d requests (%.1f%%) | %.1f req/s\n",
+		formatClock(elapsed),
+		s.completed,
+		s.totalRequests,
+		progress,
+		rps)
+}
@@ -3,7 +3,8 @@ package progress
 import (
 	"fmt"
 	"github.com/jedib0t/go-pretty/v6/progress"
-	"os"
+	"go-expert-stress-test/domain"
+	"io"
 	"sync"
 	"time"
 )
@@ -11,23 +12,56 @@ import (
 // representa o progresso e pode rastrear multiplos workders
 type Tracker struct {
 	pw           progress.Writer
+	out          io.Writer
+	color        bool
 	totalTracker *progress.Tracker
 	workers      map[int]*progress.Tracker
 	mu           sync.Mutex
 	done         bool
+	rendered     chan struct{} // fechado quando a renderizacao termina
 }
 
-// cria uma nova instancia de Tracker para o monitoramenteo de multiplos workers
-func NewProgressTracker(workers, totalRequests int) *Tracker {
+// cria uma nova instancia de Tracker que desenha em out; as barras de cada worker sao criadas
+// quando o teste inicia
+func NewProgressTracker(out io.Writer, color bool) *Tracker {
+	return &Tracker{
+		out:     out,
+		color:   color,
+		workers: make(map[int]*progress.Tracker),
+	}
+}
+
+// reage aos eventos do teste desenhando uma barra total e uma por worker
+func (pt *Tracker) OnEvent(event domain.Event) {
+	switch event.Type {
+	case domain.EventStarted:
+		pt.start(min(event.Config.Concurrency, event.Config.Requests), event.Config.Requests)
+	case domain.EventRequestDone:
+		pt.IncrementWorker(event.WorkerID)
+	case domain.EventWorkerFinished:
+		pt.finishWorker(event.WorkerID)
+	case domain.EventFinished:
+		pt.Stop()
+	}
+}
+
+// configura o monitoramenteo de multiplos workers e inicia a renderizacao; cada teste ganha
+// barras novas, entao o Tracker pode ser reutilizado em execucoes seguidas
+func (pt *Tracker) start(workers, totalRequests int) {
+	pt.Stop()
+
 	pw := progress.NewWriter()
-	pw.SetAutoStop(false)
+	// a renderizacao termina sozinha quando todas as barras estao concluidas
+	pw.SetAutoStop(true)
 	pw.SetTrackerLength(40)
 	pw.SetNumTrackersExpected(workers + 1)
 	pw.SetStyle(progress.StyleBlocks)
 	pw.SetUpdateFrequency(time.Millisecond * 100)
-	pw.Style().Colors = progress.StyleColorsExample
+	if pt.color {
+		pw.Style().Colors = progress.StyleColorsExample
+	}
 	pw.Style().Options.PercentFormat = "%4.1f%%"
-	pw.SetOutputWriter(os.Stdout)
+	pw.SetOutputWriter(pt.out)
 
 	totalTracker := &progress.Tracker{
 		Message: "Total Progress",
@@ -36,38 +70,51 @@ func NewProgressTracker(workers, totalRequests int) *Tracker {
 	}
 	pw.AppendTracker(totalTracker)
 
-	workersMap := make(map[int]*progress.Tracker)
-	requestsPerWorker := totalRequests / workers
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	pt.workers = make(map[int]*progress.Tracker, workers)
 
+	// os workers retiram as requisicoes de uma fila compartilhada e a parcela de cada um so e
+	// conhecida no fim, entao as barras dos workers sao contadores sem total (indeterminados)
 	for i := 0; i < workers; i++ {
 		tracker := &progress.Tracker{
 			Message: fmt.Sprintf("Worker #%d", i+1),
-			Total:   int64(requestsPerWorker),
 			Units:   progress.UnitsDefault,
 		}
-		workersMap[i] = tracker
+		pt.workers[i] = tracker
 		pw.AppendTracker(tracker)
 	}
 
-	return &Tracker{
-		pw:           pw,
-		totalTracker: totalTracker,
-		workers:      workersMap,
-	}
-}
-
-func (pt *Tracker) Start() {
-	go pt.pw.Render()
+	pt.pw = pw
+	pt.totalTracker = totalTracker
+	pt.done = false
+	pt.rendered = make(chan struct{})
+	go func(rendered chan struct{}) {
+		defer close(rendered)
+		pw.Render()
+	}(pt.rendered)
 }
 
 // usa mutex para controlar a sincronia do progresso entre diferentes instancias de Trackers
+// e aguarda a ultima renderizacao para que as barras nao se misturem com o relatorio
 func (pt *Tracker) Stop() {
 	pt.mu.Lock()
-	if !pt.done {
-		pt.done = true
-		pt.pw.Stop()
+	if pt.done || pt.pw == nil {
+		pt.mu.Unlock()
+		return
 	}
+	pt.done = true
+	// concluir todas as barras encerra a renderizacao apos o ultimo desenho, mesmo que o
+	// Render ainda nao tenha comecado
+	pt.totalTracker.MarkAsDone()
+	for _, tracker := range pt.workers {
+		tracker.MarkAsDone()
+	}
+	rendered := pt.rendered
 	pt.mu.Unlock()
+
+	<-rendered
 }
 
 // usa mutex para garantir e incrementar de forma segura o progresso de um worker
@@ -80,3 +127,13 @@ func (pt *Tracker) IncrementWorker(workerID int) {
 		pt.totalTracker.Increment(1)
 	}
 }
+
+// congela a barra do worker quando ele nao tem mais requisicoes para enviar
+func (pt *Tracker) finishWorker(workerID int) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	if tracker, ok := pt.workers[workerID]; ok {
+		tracker.MarkAsDone()
+	}
+}
@@ -0,0 +1,189 @@
+// Package rawsocket implementa o adaptador de teste de carga para servicos TCP e UDP sem
+// protocolo de aplicacao: cada requisicao envia um payload e espera a resposta, terminada por
+// um delimitador ou por um numero de bytes.
+package rawsocket
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/netutil"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tamanho do buffer de leitura, suficiente para um datagrama UDP
+const readBufferSize = 64 * 1024
+
+// parametros das conexoes TCP/UDP
+type Options struct {
+	Payload           []byte        // enviado em cada requisicao
+	Delimiter         []byte        // a resposta termina ao receber este delimitador
+	ResponseBytes     int           // a resposta termina apos este numero de bytes
+	ConnectTimeout    time.Duration // tempo maximo para abrir a conexao
+	Timeout           time.Duration // tempo maximo ate receber a resposta completa
+	DisableKeepAlives bool          // abre uma nova conexao para cada requisicao
+}
+
+// sem delimitador nem numero de bytes, a primeira leitura (ou datagrama) encerra a resposta
+func DefaultOptions() Options {
+	return Options{
+		Payload:        []byte("ping\n"),
+		ConnectTimeout: 10 * time.Second,
+		Timeout:        30 * time.Second,
+	}
+}
+
+// indica se a url deve ser testada com o adaptador TCP/UDP
+func IsRawURL(url string) bool {
+	return strings.HasPrefix(url, "tcp://") || strings.HasPrefix(url, "udp://")
+}
+
+// cliente TCP/UDP; cada sessao (worker) mantem sua propria conexao entre as requisicoes
+type Client struct {
+	opts Options
+
+	mu   sync.Mutex
+	conn net.Conn
+	// bytes recebidos em TCP apos o fim da resposta anterior, que iniciam a proxima
+	pending []byte
+}
+
+func NewClient(opts Options) (*Client, error) {
+	if len(opts.Payload) == 0 {
+		return nil, errors.New("a payload is required")
+	}
+	if opts.ResponseBytes < 0 {
+		return nil, errors.New("response bytes must not be negative")
+	}
+	return &Client{opts: opts}, nil
+}
+
+// cada worker usa uma conexao propria
+func (c *Client) NewSession() domain.Executor {
+	return &Client{opts: c.opts}
+}
+
+// fecha a conexao da sessao; chamado pelo load tester quando o worker termina
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	c.pending = nil
+	return err
+}
+
+// envia o payload e espera a resposta; a conexao e aberta na primeira requisicao e reaberta
+// apos erros. Como nos demais adaptadores, uma resposta completa conta como 200. Em UDP nao ha
+// handshake, entao o socket nao entra nas metricas de conexao
+func (c *Client) Execute(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	network, addr, _ := strings.Cut(req.Target, "://")
+	stream := network == "tcp"
+	result := &domain.TestResult{Protocol: network}
+
+	if c.conn == nil {
+		start := time.Now()
+		dialer := &net.Dialer{Timeout: c.opts.ConnectTimeout}
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if stream {
+			result.ConnectDuration = time.Since(start)
+		}
+		if err != nil {
+			result.Error = netutil.ClassifyError(err)
+			return result, nil
+		}
+		c.conn = conn
+		result.ConnOpened = stream
+	} else {
+		result.ConnReused = stream
+	}
+	result.RemoteIP = netutil.HostOf(c.conn.RemoteAddr().String())
+
+	start := time.Now()
+	if c.opts.Timeout > 0 {
+		c.conn.SetDeadline(start.Add(c.opts.Timeout))
+	}
+
+	stop := netutil.CancelOnDone(ctx, c.conn)
+	received, err := c.roundTrip(stream)
+	interrupted := stop()
+	result.Duration = time.Since(start)
+	result.WireBytes = int64(received)
+	result.DecodedBytes = int64(received)
+
+	if err != nil || interrupted || c.opts.DisableKeepAlives {
+		c.conn.Close()
+		c.conn = nil
+		c.pending = nil
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		result.Disconnected = errors.Is(err, io.EOF)
+		result.Error = netutil.ClassifyError(err)
+		return result, nil
+	}
+
+	result.Status = http.StatusOK
+	return result, nil
+}
+
+// escreve o payload e le ate completar a resposta, retornando os bytes da resposta. Em TCP os
+// bytes lidos alem do fim da resposta ficam guardados para a proxima; em UDP cada datagrama e
+// independente e e contado por inteiro
+func (c *Client) roundTrip(stream bool) (int, error) {
+	if _, err := c.conn.Write(c.opts.Payload); err != nil {
+		return 0, err
+	}
+
+	response := bytes.NewBuffer(c.pending)
+	c.pending = nil
+	buf := make([]byte, readBufferSize)
+	var readErr error
+	for {
+		if end := c.responseEnd(response.Bytes()); end > 0 {
+			if !stream {
+				return response.Len(), nil
+			}
+			c.pending = bytes.Clone(response.Bytes()[end:])
+			return end, nil
+		}
+		if readErr != nil {
+			return response.Len(), readErr
+		}
+		n, err := c.conn.Read(buf)
+		response.Write(buf[:n])
+		readErr = err
+	}
+}
+
+// retorna o tamanho da resposta completa no inicio de response, ou zero se ainda incompleta
+func (c *Client) responseEnd(response []byte) int {
+	switch {
+	case len(c.opts.Delimiter) > 0:
+		if i := bytes.Index(response, c.opts.Delimiter); i >= 0 {
+			return i + len(c.opts.Delimiter)
+		}
+		return 0
+	case c.opts.ResponseBytes > 0:
+		if len(response) >= c.opts.ResponseBytes {
+			return c.opts.ResponseBytes
+		}
+		return 0
+	}
+	return len(response)
+}
@@ -0,0 +1,166 @@
+// Package sse implementa o adaptador de teste de carga para streams Server-Sent Events: cada
+// requisicao do teste mantem um stream aberto e mede o tempo ate o primeiro evento, os
+// intervalos entre eventos e as desconexoes antes do fim esperado.
+package sse
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/infra/netutil"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// tamanho maximo de uma linha do stream
+const maxLineSize = 1 << 20
+
+// parametros dos streams SSE
+type Options struct {
+	HTTP      httpclient.Options // transporte, TLS, proxy, autenticacao, --resolve e alvos unix://
+	Hold      time.Duration      // tempo que cada stream fica aberto
+	MaxEvents int                // encerra o stream apos este numero de eventos, 0 para nao limitar
+}
+
+// opcoes padrao: cada stream fica aberto por 30 segundos
+func DefaultOptions() Options {
+	opts := Options{
+		HTTP: httpclient.DefaultOptions(0),
+		Hold: 30 * time.Second,
+	}
+	opts.HTTP.ResponseHeaderTimeout = 30 * time.Second
+	return opts
+}
+
+// cliente SSE; as requisicoes sao enviadas pelo cliente HTTP, que aplica as mesmas opcoes de
+// conexao e autenticacao dos testes HTTP
+type Client struct {
+	http      *httpclient.Client
+	hold      time.Duration
+	maxEvents int
+}
+
+// retorna erro quando o stream nao tem limite de duracao nem de eventos ou quando as opcoes
+// HTTP sao invalidas
+func NewClient(opts Options) (*Client, error) {
+	if opts.Hold <= 0 && opts.MaxEvents <= 0 {
+		return nil, errors.New("sse streams need a hold duration or a maximum number of events")
+	}
+
+	// sem timeout total, a duracao do stream e controlada por Hold; o stream e lido sem
+	// compressao para que os eventos possam ser separados conforme chegam
+	opts.HTTP.Timeout = 0
+	opts.HTTP.AcceptEncoding = "none"
+	client, err := httpclient.NewClient(opts.HTTP)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{http: client, hold: opts.Hold, maxEvents: opts.MaxEvents}, nil
+}
+
+// cria uma sessao com o cookie jar (e, com PoolPerWorker, o pool de conexoes) proprio do worker
+func (c *Client) NewSession() domain.Executor {
+	session := *c
+	session.http = c.http.NewSession().(*httpclient.Client)
+	return &session
+}
+
+// libera as conexoes ociosas do pool proprio da sessao
+func (c *Client) Close() error {
+	return c.http.Close()
+}
+
+// abre um stream e o consome ate o fim de Hold ou de MaxEvents. Duration e o tempo ate o
+// primeiro evento (ou ate os cabecalhos, quando nenhum evento chega); o stream encerrado
+// pelo servidor antes disso e registrado como desconexao prematura
+func (c *Client) Execute(ctx context.Context, r domain.Request) (*domain.TestResult, error) {
+	if c.hold > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.hold)
+		defer cancel()
+	}
+
+	header := http.Header{
+		"Accept":        {"text/event-stream"},
+		"Cache-Control": {"no-cache"},
+	}
+	resp, result := c.http.Open(ctx, r, header)
+	if result.Error != nil {
+		return result, nil
+	}
+	defer resp.Body.Close()
+
+	// o tempo ate o primeiro evento conta a partir do envio, sem a busca de token
+	start := time.Now().Add(-result.Duration)
+
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return result, nil
+	}
+
+	stream := &domain.StreamStats{}
+	result.Stream = stream
+
+	var last time.Time
+	hasData := false
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			// apenas linhas data: formam um evento, comentarios (:) mantem a conexao viva
+			if strings.HasPrefix(line, "data") {
+				hasData = true
+			}
+			continue
+		}
+		if !hasData {
+			continue
+		}
+		hasData = false
+
+		// linha em branco despacha o evento
+		now := time.Now()
+		if stream.Events == 0 {
+			stream.FirstEvent = now.Sub(start)
+			result.Duration = stream.FirstEvent
+		} else {
+			gap := now.Sub(last)
+			stream.TotalGap += gap
+			stream.MaxGap = max(stream.MaxGap, gap)
+		}
+		last = now
+		stream.Events++
+
+		if c.maxEvents > 0 && stream.Events >= c.maxEvents {
+			break
+		}
+	}
+	stream.Held = time.Since(start)
+
+	// o stream so pode terminar pelo fim de Hold ou ao atingir MaxEvents
+	completed := ctx.Err() != nil || c.maxEvents > 0 && stream.Events >= c.maxEvents
+	if err := scanner.Err(); err != nil && !completed {
+		// o corpo interrompido no meio de um chunk tambem e um encerramento pelo servidor, os
+		// demais erros de leitura nao sao desconexoes
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			result.Error = readError(err)
+			return result, nil
+		}
+	}
+	result.Disconnected = !completed
+	return result, nil
+}
+
+// linhas maiores que maxLineSize nao podem ser interpretadas; os demais erros vem da conexao
+func readError(err error) error {
+	if errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("%w: %v", domain.ErrDecode, err)
+	}
+	return netutil.ClassifyError(err)
+}
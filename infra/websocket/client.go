@@ -0,0 +1,156 @@
+// Package websocket implementa o adaptador de teste de carga para servicos WebSocket: cada
+// worker mantem uma conexao e cada requisicao do teste e uma mensagem enviada com o tempo
+// de ida e volta ate a resposta.
+package websocket
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"github.com/gorilla/websocket"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/netutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tempo maximo para enviar o close frame ao encerrar a sessao
+const closeTimeout = time.Second
+
+// parametros das conexoes WebSocket
+type Options struct {
+	ConnectTimeout time.Duration // tempo maximo do handshake de abertura
+	Timeout        time.Duration // tempo maximo ate receber a resposta de cada mensagem
+	Messages       []string      // roteiro de mensagens, enviado em ciclo por cada conexao
+	Insecure       bool          // nao valida o certificado do servidor em wss://
+}
+
+// opcoes padrao, com os mesmos timeouts do cliente HTTP
+func DefaultOptions() Options {
+	return Options{
+		ConnectTimeout: 10 * time.Second,
+		Timeout:        30 * time.Second,
+		Messages:       []string{"ping"},
+	}
+}
+
+// indica se a url deve ser testada com o adaptador WebSocket
+func IsWebSocketURL(url string) bool {
+	return strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://")
+}
+
+// cliente WebSocket; cada sessao criada por NewSession mantem sua propria conexao, de forma
+// que o numero de workers define o numero de conexoes simultaneas
+type Client struct {
+	opts   Options
+	dialer *websocket.Dialer
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+	next int // proxima mensagem do roteiro
+}
+
+func NewClient(opts Options) (*Client, error) {
+	if len(opts.Messages) == 0 {
+		return nil, errors.New("at least one websocket message is required")
+	}
+
+	return &Client{
+		opts: opts,
+		dialer: &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: opts.ConnectTimeout,
+			TLSClientConfig:  &tls.Config{InsecureSkipVerify: opts.Insecure},
+		},
+	}, nil
+}
+
+// cada worker usa uma conexao propria, como um usuario independente
+func (c *Client) NewSession() domain.Executor {
+	return &Client{opts: c.opts, dialer: c.dialer}
+}
+
+// encerra a conexao da sessao enviando um close frame, como um cliente que sai normalmente;
+// chamado pelo load tester quando o worker termina
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(closeTimeout))
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// envia a proxima mensagem do roteiro e espera a resposta; a primeira chamada (e a seguinte
+// a uma desconexao) abre a conexao, com o tempo de abertura registrado a parte. Para entrar
+// na mesma distribuicao de status do HTTP, uma resposta recebida conta como 200
+func (c *Client) Execute(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := &domain.TestResult{Protocol: "websocket"}
+
+	if c.conn == nil {
+		start := time.Now()
+		conn, resp, err := c.dialer.DialContext(ctx, req.Target, nil)
+		result.ConnectDuration = time.Since(start)
+		if err != nil {
+			// o servidor recusou o upgrade, o status HTTP entra na distribuicao
+			if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+				result.Status = resp.StatusCode
+				return result, nil
+			}
+			result.Error = netutil.ClassifyError(err)
+			return result, nil
+		}
+		c.conn = conn
+		result.ConnOpened = true
+		result.RemoteIP = netutil.HostOf(conn.RemoteAddr().String())
+	} else {
+		result.ConnReused = true
+		result.RemoteIP = netutil.HostOf(c.conn.RemoteAddr().String())
+	}
+
+	message := c.opts.Messages[c.next%len(c.opts.Messages)]
+	c.next++
+
+	start := time.Now()
+	if c.opts.Timeout > 0 {
+		c.conn.SetReadDeadline(start.Add(c.opts.Timeout))
+	}
+
+	conn := c.conn
+	stop := netutil.CancelOnDone(ctx, conn.NetConn())
+	err := conn.WriteMessage(websocket.TextMessage, []byte(message))
+	if err == nil {
+		_, _, err = conn.ReadMessage()
+	}
+	interrupted := stop()
+	result.Duration = time.Since(start)
+
+	if err == nil && interrupted {
+		c.conn.Close()
+		c.conn = nil
+	}
+	if err != nil {
+		// a conexao e descartada e sera reaberta na proxima mensagem
+		c.conn.Close()
+		c.conn = nil
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		result.Disconnected = ctx.Err() == nil && !netutil.IsTimeout(err)
+		result.Error = netutil.ClassifyError(err)
+		return result, nil
+	}
+
+	result.Status = http.StatusOK
+	return result, nil
+}
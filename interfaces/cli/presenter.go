@@ -3,26 +3,25 @@ package cli
 import (
 	"fmt"
 	"go-expert-stress-test/domain"
+	"io"
+	"sort"
 	"strings"
 	"time"
 )
 
-const (
-	bold        = "\033[1m"
-	colorReset  = "\033[0m"
-	colorRed    = "\033[31m"
-	colorGreen  = "\033[32m"
-	colorYellow = "\033[33m"
-	colorBlue   = "\033[34m"
-	colorPurple = "\033[35m"
-	colorCyan   = "\033[36m"
-	colorGray   = "\033[37m"
-)
-
-type ReportPresenter struct{}
+type ReportPresenter struct {
+	out    io.Writer
+	output OutputOptions
+	colors palette
+}
 
-func NewReportPresenter() *ReportPresenter {
-	return &ReportPresenter{}
+// cria o presenter que escreve os relatorios em out
+func NewReportPresenter(out io.Writer, output OutputOptions) *ReportPresenter {
+	return &ReportPresenter{
+		out:    out,
+		output: output,
+		colors: output.palette(),
+	}
 }
 
 // banerzinho pra fazer um fru-fru :)
@@ -33,50 +32,50 @@ func (p *ReportPresenter) displayBanner() {
 █  ╚═╗ ║ ╠╦╝║╣ ╚═╗╚═╗   ║ ║╣ ╚═╗ ║ ║╣ ╠╦╝  ▄▄▄▄▄▄▄  █
 █  ╚═╝ ╩ ╩╚═╚═╝╚═╝╚═╝   ╩ ╚═╝╚═╝ ╩ ╚═╝╩╚═  ▀▀▀▀▀▀▀  █
 █▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄█`
-	fmt.Printf("%s%s%s\n", colorCyan, banner, colorReset)
+	fmt.Fprintf(p.out, "%s%s%s\n", p.colors.cyan, banner, p.colors.reset)
 }
 
 // renderiza a barra de progresso de cada worker
 func (p *ReportPresenter) DisplayProgressBar(current, total int) {
 	width := 50
-	progress := float64(current) / float64(total)
+	progress := percent(current, total) / 100
 	completed := int(progress * float64(width))
 
-	fmt.Printf("\r%s[", colorGray)
+	fmt.Fprintf(p.out, "\r%s[", p.colors.gray)
 	for i := 0; i < width; i++ {
 		if i < completed {
-			fmt.Printf("%s█", colorGreen)
+			fmt.Fprintf(p.out, "%s█", p.colors.green)
 		} else {
-			fmt.Printf("%s░", colorGray)
+			fmt.Fprintf(p.out, "%s░", p.colors.gray)
 		}
 	}
-	fmt.Printf("%s] %d/%d (%.1f%%)%s",
-		colorGray,
+	fmt.Fprintf(p.out, "%s] %d/%d (%.1f%%)%s",
+		p.colors.gray,
 		current,
 		total,
 		progress*100,
-		colorReset)
+		p.colors.reset)
 }
 
 // obtem uma cor especifica para cada tipo de status code, outros codigos usan cor cinza como padrao
 func (p *ReportPresenter) getStatusColor(status int) string {
 	switch {
 	case status >= 500:
-		return colorRed
+		return p.colors.red
 	case status >= 400:
-		return colorYellow
+		return p.colors.yellow
 	case status >= 300:
-		return colorBlue
+		return p.colors.blue
 	case status >= 200:
-		return colorGreen
+		return p.colors.green
 	default:
-		return colorGray
+		return p.colors.gray
 	}
 }
 
 // printa o resultados de cada http status code
 func (p *ReportPresenter) displayStatusGraph(distrib map[int]int, total int) {
-	fmt.Printf("\n\n%s%s= Distribuição de Status HTTP =%s\n", bold, colorPurple, colorReset)
+	fmt.Fprintf(p.out, "\n\n%s%s= Distribuição de Status HTTP =%s\n", p.colors.bold, p.colors.purple, p.colors.reset)
 
 	// encontra o maior valor para escalar o gráfico
 	maxCount := 0
@@ -89,19 +88,19 @@ func (p *ReportPresenter) displayStatusGraph(distrib map[int]int, total int) {
 
 	// ordena e exibir as barras
 	for status, count := range distrib {
-		percentage := float64(count) / float64(total) * 100
+		percentage := percent(count, total)
 		barWidth := int(float64(count) / float64(maxCount) * float64(maxWidth))
 
 		statusColor := p.getStatusColor(status)
 		bar := strings.Repeat("█", barWidth)
 
-		fmt.Printf("%sHTTP %d %s[%s%s%s] %d (%.1f%%)\n",
-			bold,
+		fmt.Fprintf(p.out, "%sHTTP %d %s[%s%s%s] %d (%.1f%%)\n",
+			p.colors.bold,
 			status,
-			colorGray,
+			p.colors.gray,
 			statusColor,
 			bar,
-			colorGray,
+			p.colors.gray,
 			count,
 			percentage,
 		)
@@ -110,40 +109,218 @@ func (p *ReportPresenter) displayStatusGraph(distrib map[int]int, total int) {
 
 // imprime os resultados do teste
 func (p *ReportPresenter) Present(report *domain.TestReport) {
+	if p.output.Quiet {
+		p.displaySummaryLine(report)
+		return
+	}
+
+	// limpa a tela para que o progresso nao se misture com o relatorio
+	if p.output.Interactive {
+		fmt.Fprint(p.out, clearScreen)
+	}
+
 	p.displayBanner()
 
-	fmt.Printf("\n%s%s[RELATÓRIO DE TESTE DE CARGA]%s\n", bold, colorCyan, colorReset)
-	fmt.Printf("\n%s▶ Métricas Gerais%s\n", colorPurple, colorReset)
-	fmt.Printf("  • Duração Total: %s%v%s\n", colorGreen, report.TotalDuration.Round(time.Millisecond), colorReset)
-	fmt.Printf("  • Total Requests: %s%d%s\n", colorGreen, report.TotalRequests, colorReset)
-	fmt.Printf("  • Média por Request: %s%v%s\n", colorGreen, report.AverageDuration.Round(time.Millisecond), colorReset)
+	fmt.Fprintf(p.out, "\n%s%s[RELATÓRIO DE TESTE DE CARGA]%s\n", p.colors.bold, p.colors.cyan, p.colors.reset)
+	fmt.Fprintf(p.out, "\n%s▶ Métricas Gerais%s\n", p.colors.purple, p.colors.reset)
+	fmt.Fprintf(p.out, "  • Duração Total: %s%v%s\n", p.colors.green, report.TotalDuration.Round(time.Millisecond), p.colors.reset)
+	fmt.Fprintf(p.out, "  • Total Requests: %s%d%s\n", p.colors.green, report.TotalRequests, p.colors.reset)
+	fmt.Fprintf(p.out, "  • Média por Request: %s%v%s\n", p.colors.green, report.AverageDuration.Round(time.Millisecond), p.colors.reset)
+	fmt.Fprintf(p.out, "  • Requests/s: %s%.1f%s\n", p.colors.green, report.Throughput, p.colors.reset)
+	fmt.Fprintf(p.out, "  • Percentis: %sp50 %v, p95 %v, p99 %v%s\n",
+		p.colors.green,
+		report.P50.Round(time.Millisecond),
+		report.P95.Round(time.Millisecond),
+		report.P99.Round(time.Millisecond),
+		p.colors.reset)
 
-	successRate := float64(report.SuccessRequests) / float64(report.TotalRequests) * 100
-	fmt.Printf("\n%s▶ Taxa de Sucesso%s\n", colorPurple, colorReset)
-	fmt.Printf("  • Requests OK (2xx): %s%d (%.1f%%)%s\n",
-		colorGreen,
+	successRate := percent(report.SuccessRequests, report.TotalRequests)
+	fmt.Fprintf(p.out, "\n%s▶ Taxa de Sucesso%s\n", p.colors.purple, p.colors.reset)
+	fmt.Fprintf(p.out, "  • Requests OK (2xx): %s%d (%.1f%%)%s\n",
+		p.colors.green,
 		report.SuccessRequests,
 		successRate,
-		colorReset)
+		p.colors.reset)
 
 	if report.ErrorCount > 0 {
-		errorRate := float64(report.ErrorCount) / float64(report.TotalRequests) * 100
-		fmt.Printf("  • Erros: %s%d (%.1f%%)%s\n",
-			colorRed,
+		errorRate := percent(report.ErrorCount, report.TotalRequests)
+		fmt.Fprintf(p.out, "  • Erros: %s%d (%.1f%%)%s\n",
+			p.colors.red,
 			report.ErrorCount,
 			errorRate,
-			colorReset)
+			p.colors.reset)
+		for _, class := range sortedKeys(report.ErrorsByClass) {
+			fmt.Fprintf(p.out, "    - %s: %d\n", class, report.ErrorsByClass[class])
+		}
+		for _, message := range sortedKeys(report.GraphQLErrors) {
+			fmt.Fprintf(p.out, "    - graphql \"%s\": %d\n", message, report.GraphQLErrors[message])
+		}
+	}
+
+	if report.Retried > 0 {
+		firstRate := percent(report.FirstAttemptOK, report.TotalRequests)
+		fmt.Fprintf(p.out, "\n%s▶ Retries%s\n", p.colors.purple, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Sucesso na primeira tentativa: %s%d (%.1f%%)%s\n", p.colors.green, report.FirstAttemptOK, firstRate, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Sucesso após retries: %s%d (%.1f%%)%s\n", p.colors.green, report.SuccessRequests, successRate, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Requests repetidas: %s%d (%d retries, %d recuperadas)%s\n",
+			p.colors.yellow,
+			report.Retried,
+			report.Retries,
+			report.RetriesRecovered,
+			p.colors.reset)
+		fmt.Fprintf(p.out, "  • Latência extra média: %s%v%s\n", p.colors.yellow, report.AvgRetryLatency.Round(time.Millisecond), p.colors.reset)
+	}
+
+	if report.ConnsOpened+report.ConnsReused > 0 {
+		fmt.Fprintf(p.out, "\n%s▶ Conexões%s\n", p.colors.purple, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Abertas: %s%d%s\n", p.colors.green, report.ConnsOpened, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Reutilizadas: %s%d%s\n", p.colors.green, report.ConnsReused, p.colors.reset)
+		if report.AvgConnectTime > 0 {
+			fmt.Fprintf(p.out, "  • Tempo médio de abertura: %s%v%s\n", p.colors.green, report.AvgConnectTime.Round(time.Microsecond), p.colors.reset)
+		}
+		if report.Disconnects > 0 {
+			fmt.Fprintf(p.out, "  • Desconexões: %s%d%s\n", p.colors.red, report.Disconnects, p.colors.reset)
+		}
+	}
+
+	if suites := report.AttributeDistrib[domain.AttributeTLS]; len(suites) > 0 {
+		fmt.Fprintf(p.out, "\n%s▶ TLS%s\n", p.colors.purple, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Handshakes: %s%d (média %v)%s\n",
+			p.colors.green,
+			report.TLSHandshakes,
+			report.AvgTLSHandshake.Round(time.Microsecond),
+			p.colors.reset)
+		for _, suite := range sortedKeys(suites) {
+			fmt.Fprintf(p.out, "  • %s: %s%d%s\n", suite, p.colors.green, suites[suite], p.colors.reset)
+		}
+	}
+
+	if len(report.IPStats) > 0 {
+		fmt.Fprintf(p.out, "\n%s▶ Endereços%s\n", p.colors.purple, p.colors.reset)
+		for _, ip := range sortedKeys(report.IPStats) {
+			stats := report.IPStats[ip]
+			errorRate := percent(stats.Errors, stats.Requests)
+			fmt.Fprintf(p.out, "  • %s: %s%d requests, média %v, erros %.1f%%%s\n",
+				ip,
+				p.colors.green,
+				stats.Requests,
+				stats.AverageDuration.Round(time.Millisecond),
+				errorRate,
+				p.colors.reset)
+		}
+	}
+
+	if report.Streams > 0 {
+		fmt.Fprintf(p.out, "\n%s▶ Streams SSE%s\n", p.colors.purple, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Streams: %s%d%s\n", p.colors.green, report.Streams, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Eventos: %s%d (%.1f/s)%s\n", p.colors.green, report.Events, report.EventsPerSecond, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Tempo médio aberto: %s%v%s\n", p.colors.green, report.AvgStreamHeld.Round(time.Millisecond), p.colors.reset)
+		fmt.Fprintf(p.out, "  • Tempo médio até o primeiro evento: %s%v%s\n", p.colors.green, report.AvgFirstEvent.Round(time.Millisecond), p.colors.reset)
+		fmt.Fprintf(p.out, "  • Intervalo entre eventos: %smédia %v, máximo %v%s\n",
+			p.colors.green,
+			report.AvgEventGap.Round(time.Millisecond),
+			report.MaxEventGap.Round(time.Millisecond),
+			p.colors.reset)
+		fmt.Fprintf(p.out, "  • Desconexões prematuras: %s%d%s\n", p.colors.red, report.Disconnects, p.colors.reset)
+	}
+
+	if report.WireBytes > 0 {
+		fmt.Fprintf(p.out, "\n%s▶ Compressão%s\n", p.colors.purple, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Bytes recebidos: %s%d (%d descomprimidos, razão %.2fx)%s\n",
+			p.colors.green,
+			report.WireBytes,
+			report.DecodedBytes,
+			report.CompressionRatio,
+			p.colors.reset)
+		encodings := report.AttributeDistrib[domain.AttributeContentEncoding]
+		for _, encoding := range sortedKeys(encodings) {
+			fmt.Fprintf(p.out, "  • %s: %s%d%s\n", encoding, p.colors.green, encodings[encoding], p.colors.reset)
+		}
+	}
+
+	if report.Redirected > 0 {
+		fmt.Fprintf(p.out, "\n%s▶ Redirects%s\n", p.colors.purple, p.colors.reset)
+		fmt.Fprintf(p.out, "  • Requests redirecionadas: %s%d (%d redirects)%s\n",
+			p.colors.green,
+			report.Redirected,
+			report.RedirectHops,
+			p.colors.reset)
+		for _, chain := range sortedKeys(report.RedirectChains) {
+			fmt.Fprintf(p.out, "  • %s: %s%d%s\n", chain, p.colors.green, report.RedirectChains[chain], p.colors.reset)
+		}
+	}
+
+	// os atributos TLS e de codificacao ja aparecem nas respectivas secoes
+	for _, key := range sortedKeys(report.AttributeDistrib) {
+		if key == domain.AttributeTLS || key == domain.AttributeContentEncoding {
+			continue
+		}
+		fmt.Fprintf(p.out, "\n%s▶ %s%s\n", p.colors.purple, key, p.colors.reset)
+		values := report.AttributeDistrib[key]
+		for _, value := range sortedKeys(values) {
+			fmt.Fprintf(p.out, "  • %s: %s%d%s\n", value, p.colors.green, values[value], p.colors.reset)
+		}
+	}
+
+	if len(report.ProtocolDistrib) > 0 {
+		fmt.Fprintf(p.out, "\n%s▶ Protocolos%s\n", p.colors.purple, p.colors.reset)
+		for _, proto := range sortedKeys(report.ProtocolDistrib) {
+			fmt.Fprintf(p.out, "  • %s: %s%d%s\n", proto, p.colors.green, report.ProtocolDistrib[proto], p.colors.reset)
+		}
 	}
 
 	p.displayStatusGraph(report.StatusDistrib, report.TotalRequests)
 
 	// sumario final do test de carga
-	fmt.Printf("\n%s%s[SUMÁRIO]%s\n", bold, colorCyan, colorReset)
+	fmt.Fprintf(p.out, "\n%s%s[SUMÁRIO]%s\n", p.colors.bold, p.colors.cyan, p.colors.reset)
 	if successRate >= 95 {
-		fmt.Printf("%s✓ Sistema respondeu bem ao teste de carga%s\n", colorGreen, colorReset)
+		fmt.Fprintf(p.out, "%s✓ Sistema respondeu bem ao teste de carga%s\n", p.colors.green, p.colors.reset)
 	} else if successRate >= 80 {
-		fmt.Printf("%s⚠ Sistema apresentou algumas instabilidades%s\n", colorYellow, colorReset)
+		fmt.Fprintf(p.out, "%s⚠ Sistema apresentou algumas instabilidades%s\n", p.colors.yellow, p.colors.reset)
 	} else {
-		fmt.Printf("%s✗ Sistema apresentou problemas significativos%s\n", colorRed, colorReset)
+		fmt.Fprintf(p.out, "%s✗ Sistema apresentou problemas significativos%s\n", p.colors.red, p.colors.reset)
+	}
+}
+
+// resumo em uma unica linha, usado no modo silencioso
+func (p *ReportPresenter) displaySummaryLine(report *domain.TestReport) {
+	statuses := make([]int, 0, len(report.StatusDistrib))
+	for status := range report.StatusDistrib {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	distrib := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		distrib = append(distrib, fmt.Sprintf("%d=%d", status, report.StatusDistrib[status]))
+	}
+
+	fmt.Fprintf(p.out, "duration=%v requests=%d ok=%d errors=%d avg=%v conns_opened=%d conns_reused=%d status=[%s]\n",
+		report.TotalDuration.Round(time.Millisecond),
+		report.TotalRequests,
+		report.SuccessRequests,
+		report.ErrorCount,
+		report.AverageDuration.Round(time.Millisecond),
+		report.ConnsOpened,
+		report.ConnsReused,
+		strings.Join(distrib, " "))
+}
+
+// porcentagem de part em total; um relatorio sem requests (teste cancelado antes da primeira
+// resposta) mostra 0% em vez de NaN
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// retorna as chaves de um map em ordem, para que a saida seja estavel
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"fmt"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/observer"
+	"io"
+	"strings"
+	"time"
+)
+
+// imprime uma linha ao fim de cada degrau da busca de vazao maxima
+type SearchProgress struct {
+	out    io.Writer
+	colors palette
+	label  string
+}
+
+// cria o acompanhamento da busca, escrito em out; no modo silencioso nada e impresso
+func NewSearchProgress(out io.Writer, output OutputOptions, mode string) domain.ProgressObserver {
+	if output.Quiet {
+		return observer.NewNoop()
+	}
+	return &SearchProgress{out: out, colors: output.palette(), label: searchLevelLabel(mode)}
+}
+
+func (sp *SearchProgress) OnEvent(event domain.Event) {
+	if event.Type != domain.EventStepDone {
+		return
+	}
+	step := event.Step
+	color := sp.colors.green
+	if step.Breach != "" {
+		color = sp.colors.red
+	}
+	fmt.Fprintf(sp.out, "%s%s %d%s: %.1f req/s, p99 %v, erros %.1f%%\n",
+		color,
+		sp.label,
+		step.Level,
+		sp.colors.reset,
+		step.Throughput,
+		step.P99.Round(time.Millisecond),
+		step.ErrorRate)
+}
+
+// imprime a tabela de degraus, a curva de vazao e o ultimo nivel sustentavel
+func (p *ReportPresenter) PresentSearch(report *domain.SearchReport) {
+	label := searchLevelLabel(report.Config.Mode)
+
+	if p.output.Quiet {
+		sustainable, throughput := 0, 0.0
+		if report.Sustainable != nil {
+			sustainable, throughput = report.Sustainable.Level, report.Sustainable.Throughput
+		}
+		fmt.Fprintf(p.out, "mode=%s sustainable=%d throughput=%.1f steps=%d breach=%s\n",
+			report.Config.Mode,
+			sustainable,
+			throughput,
+			len(report.Steps),
+			lastBreach(report))
+		return
+	}
+
+	p.displayBanner()
+
+	fmt.Fprintf(p.out, "\n%s%s[BUSCA DE VAZÃO MÁXIMA]%s\n", p.colors.bold, p.colors.cyan, p.colors.reset)
+	fmt.Fprintf(p.out, "\n%s▶ Limites%s\n", p.colors.purple, p.colors.reset)
+	if report.Config.MaxP99 > 0 {
+		fmt.Fprintf(p.out, "  • p99 máximo: %s%v%s\n", p.colors.green, report.Config.MaxP99, p.colors.reset)
+	}
+	fmt.Fprintf(p.out, "  • Taxa de erros máxima: %s%.1f%%%s\n", p.colors.green, report.Config.MaxErrorRate, p.colors.reset)
+
+	fmt.Fprintf(p.out, "\n%s▶ Degraus%s\n", p.colors.purple, p.colors.reset)
+	fmt.Fprintf(p.out, "  %-12s %10s %10s %10s %8s  %s\n", label, "Req/s", "p50", "p99", "Erros", "Situação")
+	for _, step := range report.Steps {
+		situation := p.colors.green + "ok" + p.colors.reset
+		if step.Breach != "" {
+			situation = p.colors.red + breachLabel(step.Breach) + p.colors.reset
+		}
+		fmt.Fprintf(p.out, "  %-12d %10.1f %10v %10v %7.1f%%  %s\n",
+			step.Level,
+			step.Throughput,
+			step.Report.P50.Round(time.Millisecond),
+			step.P99.Round(time.Millisecond),
+			step.ErrorRate,
+			situation)
+	}
+
+	p.displayKneeChart(report)
+
+	fmt.Fprintf(p.out, "\n%s%s[SUMÁRIO]%s\n", p.colors.bold, p.colors.cyan, p.colors.reset)
+	switch {
+	case report.Sustainable == nil:
+		fmt.Fprintf(p.out, "%s✗ Nenhum nível sustentável: o primeiro degrau já ultrapassou os limites%s\n", p.colors.red, p.colors.reset)
+	case lastBreach(report) == "":
+		fmt.Fprintf(p.out, "%s⚠ Limites não atingidos até %s %d (%.1f req/s); aumente o nível máximo%s\n",
+			p.colors.yellow, strings.ToLower(label), report.Sustainable.Level, report.Sustainable.Throughput, p.colors.reset)
+	default:
+		fmt.Fprintf(p.out, "%s✓ Último nível sustentável: %s %d (%.1f req/s, p99 %v)%s\n",
+			p.colors.green,
+			strings.ToLower(label),
+			report.Sustainable.Level,
+			report.Sustainable.Throughput,
+			report.Sustainable.P99.Round(time.Millisecond),
+			p.colors.reset)
+	}
+}
+
+// grafico de barras da vazao por nivel; o joelho da curva aparece quando as barras param de
+// crescer junto com o nivel
+func (p *ReportPresenter) displayKneeChart(report *domain.SearchReport) {
+	if len(report.Steps) == 0 {
+		return
+	}
+	fmt.Fprintf(p.out, "\n\n%s%s= Curva de Vazão =%s\n", p.colors.bold, p.colors.purple, p.colors.reset)
+
+	maxThroughput := 0.0
+	for _, step := range report.Steps {
+		maxThroughput = max(maxThroughput, step.Throughput)
+	}
+	maxWidth := 40
+
+	for _, step := range report.Steps {
+		barWidth := 0
+		if maxThroughput > 0 {
+			barWidth = int(step.Throughput / maxThroughput * float64(maxWidth))
+		}
+
+		color, marker := p.colors.green, ""
+		switch {
+		case step.Breach != "":
+			color, marker = p.colors.red, " ✗"
+		case report.Sustainable != nil && step.Level == report.Sustainable.Level:
+			color, marker = p.colors.cyan, " ◀ último nível sustentável"
+		}
+
+		fmt.Fprintf(p.out, "%s%6d %s[%s%s%s] %.1f req/s%s%s\n",
+			p.colors.bold,
+			step.Level,
+			p.colors.gray,
+			color,
+			strings.Repeat("█", barWidth)+strings.Repeat(" ", maxWidth-barWidth),
+			p.colors.gray,
+			step.Throughput,
+			marker,
+			p.colors.reset)
+	}
+}
+
+// limite ultrapassado no ultimo degrau, vazio quando a busca chegou ao nivel maximo
+func lastBreach(report *domain.SearchReport) string {
+	if len(report.Steps) == 0 {
+		return ""
+	}
+	return report.Steps[len(report.Steps)-1].Breach
+}
+
+func searchLevelLabel(mode string) string {
+	if mode == domain.SearchRate {
+		return "Taxa"
+	}
+	return "Concorrência"
+}
+
+func breachLabel(breach string) string {
+	switch breach {
+	case domain.BreachP99:
+		return "p99 acima do limite"
+	case domain.BreachErrorRate:
+		return "erros acima do limite"
+	}
+	return breach
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/observer"
+	"go-expert-stress-test/infra/progress"
+	"golang.org/x/term"
+	"os"
+	"time"
+)
+
+const (
+	clearScreen = "\033[H\033[2J"
+
+	// intervalo das linhas de status quando a saida nao e um terminal
+	statusLineInterval = 5 * time.Second
+)
+
+// conjunto de sequencias ANSI usadas na saida, vazio quando as cores estao desabilitadas
+type palette struct {
+	bold   string
+	reset  string
+	red    string
+	green  string
+	yellow string
+	blue   string
+	purple string
+	cyan   string
+	gray   string
+}
+
+var ansiPalette = palette{
+	bold:   "\033[1m",
+	reset:  "\033[0m",
+	red:    "\033[31m",
+	green:  "\033[32m",
+	yellow: "\033[33m",
+	blue:   "\033[34m",
+	purple: "\033[35m",
+	cyan:   "\033[36m",
+	gray:   "\033[37m",
+}
+
+// define como a saida do stress tester deve ser renderizada
+type OutputOptions struct {
+	Interactive bool // stdout e um terminal e pode ser redesenhado
+	Color       bool // usa cores ANSI
+	Quiet       bool // sem progresso e sem banner, apenas o resumo final
+}
+
+// detecta se stdout e um terminal e aplica as flags --no-color e --quiet (NO_COLOR tambem desabilita as cores)
+func DetectOutput(noColor, quiet bool) OutputOptions {
+	return NewOutputOptions(term.IsTerminal(int(os.Stdout.Fd())), noColor, quiet)
+}
+
+// aplica as flags e a variavel NO_COLOR sobre uma saida que e ou nao um terminal
+func NewOutputOptions(interactive, noColor, quiet bool) OutputOptions {
+	_, noColorEnv := os.LookupEnv("NO_COLOR")
+
+	return OutputOptions{
+		Interactive: interactive,
+		Color:       interactive && !noColor && !noColorEnv,
+		Quiet:       quiet,
+	}
+}
+
+func (o OutputOptions) palette() palette {
+	if o.Color {
+		return ansiPalette
+	}
+	return palette{}
+}
+
+// escolhe o acompanhamento de progresso adequado para a saida
+func NewProgressObserver(output OutputOptions, dashboard bool) domain.ProgressObserver {
+	switch {
+	case output.Quiet:
+		return observer.NewNoop()
+	case !output.Interactive:
+		return progress.NewStatusLine(os.Stdout, statusLineInterval)
+	case dashboard:
+		return progress.NewDashboard(os.Stdout)
+	default:
+		return progress.NewProgressTracker(os.Stdout, output.Color)
+	}
+}
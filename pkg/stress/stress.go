@@ -0,0 +1,283 @@
+// Package stress expoe o stress tester como biblioteca, para que testes de carga possam ser
+// executados a partir de suites `go test` ou de outros programas Go, sem escrever no stdout.
+//
+//	report, err := stress.Run(ctx, "http://localhost:8080",
+//		stress.WithRequests(500),
+//		stress.WithConcurrency(20),
+//	)
+package stress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/grpcclient"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/infra/rawsocket"
+	"go-expert-stress-test/infra/websocket"
+	"go-expert-stress-test/usecases"
+	"time"
+)
+
+// relatorio retornado ao final de uma execucao
+type Report = domain.TestReport
+
+// evento emitido durante a execucao, recebido pelo callback de WithEventHandler
+type Event = domain.Event
+
+var ErrInvalidConfig = errors.New("invalid stress test config")
+
+// executor de requisicoes de um protocolo, recebido por WithExecutor
+type Executor = domain.Executor
+
+// politica de novas tentativas recebida por WithRetry
+type RetryPolicy = domain.RetryPolicy
+
+// degraus e limites da busca de FindMax
+type SearchConfig = domain.SearchConfig
+
+// degraus executados por FindMax e o ultimo nivel sustentavel
+type SearchReport = domain.SearchReport
+
+// pausa entre iteracoes recebida por WithThinkTime
+type ThinkTime = domain.ThinkTime
+
+// parametros do transporte HTTP usado pelo cliente padrao
+type ClientOptions = httpclient.Options
+
+// metodo, mensagem e descritores usados em alvos grpc:// e grpcs://
+type GRPCOptions = grpcclient.Options
+
+// roteiro de mensagens e timeouts usados em alvos ws:// e wss://
+type WebSocketOptions = websocket.Options
+
+// payload, fim da resposta e timeouts usados em alvos tcp:// e udp://
+type RawOptions = rawsocket.Options
+
+type settings struct {
+	config        domain.TestConfig
+	executor      Executor
+	clientOptions *ClientOptions
+	grpcOptions   GRPCOptions
+	wsOptions     *WebSocketOptions
+	rawOptions    *RawOptions
+	onEvent       func(Event)
+}
+
+// configura uma execucao de Run
+type Option func(*settings)
+
+// numero total de requisicoes, padrao 1
+func WithRequests(n int) Option {
+	return func(s *settings) { s.config.Requests = n }
+}
+
+// numero de workers simultaneos, padrao 1
+func WithConcurrency(n int) Option {
+	return func(s *settings) { s.config.Concurrency = n }
+}
+
+// limite de requisicoes por segundo, padrao sem limite
+func WithRate(rps int) Option {
+	return func(s *settings) { s.config.Rate = rps }
+}
+
+// repete as requisicoes que falharem segundo a politica; sem status e classes de erro sao
+// repetidos 502, 503, 504, timeout e connection
+func WithRetry(policy RetryPolicy) Option {
+	return func(s *settings) { s.config.Retry = policy }
+}
+
+// pausa cada worker entre uma requisicao e a proxima; a pausa nao entra na latencia
+func WithThinkTime(think ThinkTime) Option {
+	return func(s *settings) { s.config.ThinkTime = think }
+}
+
+// intervalo minimo entre o inicio de requisicoes consecutivas de cada worker, ex: uma iteracao
+// a cada 2s por usuario
+func WithPacing(interval time.Duration) Option {
+	return func(s *settings) { s.config.Pacing = interval }
+}
+
+// substitui o executor escolhido pelo esquema da url, util para mocks, protocolos customizados
+// ou adaptadores que nao sao escolhidos pelo esquema (SSE)
+func WithExecutor(executor Executor) Option {
+	return func(s *settings) { s.executor = executor }
+}
+
+// configura timeouts, pool de conexoes, keep-alive, HTTP/2 e TLS do cliente padrao; por padrao
+// httpclient.DefaultOptions. Como na CLI, MaxIdleConns e MaxIdleConnsPerHost zerados mantem uma
+// conexao ociosa por worker
+func WithClientOptions(opts ClientOptions) Option {
+	return func(s *settings) { s.clientOptions = &opts }
+}
+
+// configura a chamada gRPC feita em alvos grpc:// e grpcs://; sem Timeout, cada chamada usa o
+// limite de grpcclient.DefaultOptions
+func WithGRPCOptions(opts GRPCOptions) Option {
+	return func(s *settings) { s.grpcOptions = opts }
+}
+
+// configura as mensagens enviadas em alvos ws:// e wss://; por padrao websocket.DefaultOptions
+func WithWebSocketOptions(opts WebSocketOptions) Option {
+	return func(s *settings) { s.wsOptions = &opts }
+}
+
+// configura o payload e o fim da resposta em alvos tcp:// e udp://; por padrao
+// rawsocket.DefaultOptions
+func WithRawOptions(opts RawOptions) Option {
+	return func(s *settings) { s.rawOptions = &opts }
+}
+
+// recebe cada evento do teste; o callback pode ser chamado de varias goroutines ao mesmo tempo
+func WithEventHandler(handler func(Event)) Option {
+	return func(s *settings) { s.onEvent = handler }
+}
+
+// executa um teste de carga contra url e retorna o relatorio; se ctx for cancelado o relatorio
+// parcial e retornado junto com o erro do contexto
+func Run(ctx context.Context, url string, opts ...Option) (*Report, error) {
+	s, err := newSettings(url, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	executor, closeExecutor, err := s.newExecutor(url, s.config.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+	defer closeExecutor()
+
+	loadTester := usecases.NewLoadTesterUseCase(executor, usecases.NewReporter(), s.observer())
+	return loadTester.ExecuteContext(ctx, s.config)
+}
+
+// aumenta a concorrencia ou a taxa degrau a degrau, com WithRequests requisicoes por degrau,
+// ate o p99 ou a taxa de erros ultrapassar os limites de search; se ctx for cancelado os
+// degraus concluidos sao retornados junto com o erro do contexto
+func FindMax(ctx context.Context, url string, search SearchConfig, opts ...Option) (*SearchReport, error) {
+	s, err := newSettings(url, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := search.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	// o pool padrao guarda uma conexao ociosa por worker do maior degrau
+	executor, closeExecutor, err := s.newExecutor(url, max(s.config.Concurrency, search.Max))
+	if err != nil {
+		return nil, err
+	}
+	defer closeExecutor()
+
+	searcher := usecases.NewThroughputSearchUseCase(executor, usecases.NewReporter(), s.observer())
+	return searcher.Search(ctx, s.config, search)
+}
+
+// aplica as opcoes sobre os valores padrao e valida a configuracao resultante
+func newSettings(url string, opts []Option) (*settings, error) {
+	s := &settings{
+		config: domain.TestConfig{
+			URL:         url,
+			Requests:    1,
+			Concurrency: 1,
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := validate(s.config); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// executor de WithExecutor ou o escolhido pelo esquema da url; workers dimensiona o pool de
+// conexoes ociosas do cliente HTTP padrao
+func (s *settings) newExecutor(url string, workers int) (Executor, func(), error) {
+	if s.executor != nil {
+		return s.executor, func() {}, nil
+	}
+	executor, closeExecutor, err := newExecutor(url, s, workers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return executor, closeExecutor, nil
+}
+
+func (s *settings) observer() domain.ProgressObserver {
+	if s.onEvent == nil {
+		return nil
+	}
+	return domain.ObserverFunc(s.onEvent)
+}
+
+// escolhe o executor pelo esquema da url; a funcao retornada libera as conexoes ao fim do teste
+func newExecutor(url string, s *settings, workers int) (Executor, func(), error) {
+	noop := func() {}
+
+	switch {
+	case grpcclient.IsGRPCURL(url):
+		grpcOptions := s.grpcOptions
+		if grpcOptions.Timeout == 0 {
+			grpcOptions.Timeout = grpcclient.DefaultOptions().Timeout
+		}
+		client, err := grpcclient.NewClient(url, grpcOptions)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client, func() { client.Close() }, nil
+	case rawsocket.IsRawURL(url):
+		rawOptions := rawsocket.DefaultOptions()
+		if s.rawOptions != nil {
+			rawOptions = *s.rawOptions
+		}
+		client, err := rawsocket.NewClient(rawOptions)
+		return client, noop, err
+	case websocket.IsWebSocketURL(url):
+		wsOptions := websocket.DefaultOptions()
+		if s.wsOptions != nil {
+			wsOptions = *s.wsOptions
+		}
+		client, err := websocket.NewClient(wsOptions)
+		return client, noop, err
+	}
+
+	clientOptions := httpclient.DefaultOptions(workers)
+	if s.clientOptions != nil {
+		clientOptions = *s.clientOptions
+		// sem limite por host o net/http manteria apenas 2 conexoes ociosas
+		if clientOptions.MaxIdleConns == 0 {
+			clientOptions.MaxIdleConns = workers
+		}
+		if clientOptions.MaxIdleConnsPerHost == 0 {
+			clientOptions.MaxIdleConnsPerHost = workers
+		}
+	}
+	client, err := httpclient.NewClient(clientOptions)
+	return client, noop, err
+}
+
+func validate(config domain.TestConfig) error {
+	switch {
+	case config.URL == "":
+		return fmt.Errorf("%w: url is required", ErrInvalidConfig)
+	case config.Requests <= 0:
+		return fmt.Errorf("%w: requests must be positive", ErrInvalidConfig)
+	case config.Concurrency <= 0:
+		return fmt.Errorf("%w: concurrency must be positive", ErrInvalidConfig)
+	case config.Rate < 0:
+		return fmt.Errorf("%w: rate must not be negative", ErrInvalidConfig)
+	case config.Retry.MaxRetries < 0:
+		return fmt.Errorf("%w: retries must not be negative", ErrInvalidConfig)
+	case config.Pacing < 0:
+		return fmt.Errorf("%w: pacing must not be negative", ErrInvalidConfig)
+	}
+	if err := config.ThinkTime.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/pkg/stress"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAuthStrategies(t *testing.T) {
+	var tokenFetches atomic.Int64
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if r.FormValue("grant_type") != "client_credentials" || id != "client" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := tokenFetches.Add(1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, n)
+	}))
+	defer tokenServer.Close()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Basic dXNlcjpwYXNz", "Bearer static", "Bearer token-1":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer target.Close()
+
+	tests := []struct {
+		name         string
+		auth         httpclient.AuthOptions
+		success      int
+		authErrors   int
+		tokenFetches int64
+	}{
+		{name: "Basic", auth: httpclient.AuthOptions{Basic: "user:pass"}, success: 20},
+		{name: "Bearer estático", auth: httpclient.AuthOptions{Bearer: "static"}, success: 20},
+		{
+			name: "OAuth2 client credentials busca o token uma única vez",
+			auth: httpclient.AuthOptions{OAuth2: httpclient.OAuth2Options{
+				TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "s3cret",
+			}},
+			success:      20,
+			tokenFetches: 1,
+		},
+		{
+			name: "OAuth2 com credenciais inválidas gera erros de autenticação",
+			auth: httpclient.AuthOptions{OAuth2: httpclient.OAuth2Options{
+				TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "wrong",
+			}},
+			authErrors: 20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenFetches.Store(0)
+
+			opts := httpclient.DefaultOptions(4)
+			opts.Auth = tt.auth
+
+			report, err := stress.Run(context.Background(), target.URL,
+				stress.WithRequests(20),
+				stress.WithConcurrency(4),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.SuccessRequests != tt.success {
+				t.Errorf("Requests com sucesso incorretas: got %d, want %d (status: %v)", report.SuccessRequests, tt.success, report.StatusDistrib)
+			}
+			if report.ErrorsByClass["auth"] != tt.authErrors {
+				t.Errorf("Erros de autenticação incorretos: got %v, want %d", report.ErrorsByClass, tt.authErrors)
+			}
+			if tt.tokenFetches > 0 && tokenFetches.Load() != tt.tokenFetches {
+				t.Errorf("Buscas de token incorretas: got %d, want %d", tokenFetches.Load(), tt.tokenFetches)
+			}
+		})
+	}
+}
+
+func TestOAuth2TokenRefresh(t *testing.T) {
+	// atraso conhecido do token endpoint, que nao pode aparecer na latencia das requests
+	const tokenDelay = 300 * time.Millisecond
+
+	var tokenFetches atomic.Int64
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(tokenDelay)
+		n := tokenFetches.Add(1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":1}`, n)
+	}))
+	defer tokenServer.Close()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	opts := httpclient.DefaultOptions(1)
+	opts.Auth.OAuth2 = httpclient.OAuth2Options{TokenURL: tokenServer.URL, ClientID: "client"}
+
+	// com 5 requests por segundo e tokens de 1s, o token precisa ser renovado durante o teste
+	report, err := stress.Run(context.Background(), target.URL,
+		stress.WithRequests(15),
+		stress.WithRate(5),
+		stress.WithClientOptions(opts),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if report.SuccessRequests != 15 {
+		t.Errorf("Requests com sucesso incorretas: got %d, want 15", report.SuccessRequests)
+	}
+	if tokenFetches.Load() < 2 {
+		t.Errorf("Token deveria ser renovado durante o teste: got %d buscas", tokenFetches.Load())
+	}
+	// as requests que aguardaram a renovacao ficam acima do p99 se o atraso for contabilizado
+	if report.P99 >= tokenDelay {
+		t.Errorf("Busca de token não deveria contar na latência: p99 %v, atraso do token %v", report.P99, tokenDelay)
+	}
+}
+
+func TestOAuth2TokenEndpointDialer(t *testing.T) {
+	tokenHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token":"token-1","expires_in":3600}`)
+	})
+	targetHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// servidor http que atende apenas por unix socket
+	serveUnix := func(t *testing.T, handler http.Handler) string {
+		socketPath := filepath.Join(t.TempDir(), "app.sock")
+		listener, err := net.Listen("unix", socketPath)
+		if err != nil {
+			t.Skipf("Unix sockets indisponíveis: %v", err)
+		}
+		server := httptest.NewUnstartedServer(handler)
+		server.Listener = listener
+		server.Start()
+		t.Cleanup(server.Close)
+		return socketPath
+	}
+
+	tcpToken := httptest.NewServer(tokenHandler)
+	defer tcpToken.Close()
+	tcpTarget := httptest.NewServer(targetHandler)
+	defer tcpTarget.Close()
+	_, tokenPort, _ := net.SplitHostPort(tcpToken.Listener.Addr().String())
+
+	tests := []struct {
+		name     string
+		target   func(t *testing.T) string
+		tokenURL func(t *testing.T) string
+		resolve  []string
+	}{
+		{
+			name:     "Deve aplicar --resolve ao token endpoint",
+			target:   func(t *testing.T) string { return tcpTarget.URL },
+			tokenURL: func(t *testing.T) string { return "http://auth.stress.test:" + tokenPort + "/token" },
+			resolve:  []string{"auth.stress.test:" + tokenPort + ":127.0.0.1"},
+		},
+		{
+			name:     "Deve buscar o token por unix socket",
+			target:   func(t *testing.T) string { return tcpTarget.URL },
+			tokenURL: func(t *testing.T) string { return "unix://" + serveUnix(t, tokenHandler) + ":/token" },
+		},
+		{
+			name:     "Alvo unix não deve desviar o token endpoint para o socket",
+			target:   func(t *testing.T) string { return "unix://" + serveUnix(t, targetHandler) + ":/" },
+			tokenURL: func(t *testing.T) string { return tcpToken.URL },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.Resolve = tt.resolve
+			opts.Auth.OAuth2 = httpclient.OAuth2Options{TokenURL: tt.tokenURL(t), ClientID: "client"}
+
+			report, err := stress.Run(context.Background(), tt.target(t),
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.SuccessRequests != 10 {
+				t.Errorf("Requests com sucesso incorretas: got %d, want 10 (erros: %v, status: %v)", report.SuccessRequests, report.ErrorsByClass, report.StatusDistrib)
+			}
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package tests
 
 import (
+	"context"
 	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/observer"
+	"go-expert-stress-test/infra/progress"
 	"go-expert-stress-test/tests/mocks"
 	"go-expert-stress-test/usecases"
+	"io"
+	"sync"
 	"testing"
 	"time"
 )
@@ -39,13 +44,35 @@ func TestRequestDistribution(t *testing.T) {
 				{Duration: 10 * time.Millisecond, Error: domain.ErrTimeout},
 			},
 		},
+		{
+			name: "Deve enviar o total exato quando a divisão não é exata",
+			config: domain.TestConfig{
+				URL:         "http://test.com",
+				Requests:    7,
+				Concurrency: 3,
+			},
+			results: []domain.TestResult{
+				{Duration: 10 * time.Millisecond, Status: 200},
+			},
+		},
+		{
+			name: "Deve suportar concorrência maior que o número de requests",
+			config: domain.TestConfig{
+				URL:         "http://test.com",
+				Requests:    3,
+				Concurrency: 10,
+			},
+			results: []domain.TestResult{
+				{Duration: 10 * time.Millisecond, Status: 200},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Setup
-			mockClient := mocks.NewMockHTTPClientWithMetrics(tt.results)
-			loadTester := usecases.NewLoadTesterUseCase(mockClient, usecases.NewReporter())
+			mockClient := mocks.NewMockExecutorWithMetrics(tt.results)
+			loadTester := usecases.NewLoadTesterUseCase(mockClient, usecases.NewReporter(), observer.NewNoop())
 
 			// Execute
 			report, err := loadTester.Execute(tt.config)
@@ -91,11 +118,11 @@ func TestConcurrencyControl(t *testing.T) {
 		Concurrency: 50,
 	}
 
-	mockClient := mocks.NewMockHTTPClientWithMetrics([]domain.TestResult{
+	mockClient := mocks.NewMockExecutorWithMetrics([]domain.TestResult{
 		{Duration: 10 * time.Millisecond, Status: 200},
 	})
 
-	loadTester := usecases.NewLoadTesterUseCase(mockClient, usecases.NewReporter())
+	loadTester := usecases.NewLoadTesterUseCase(mockClient, usecases.NewReporter(), observer.NewNoop())
 
 	// Execute test with high concurrency
 	start := time.Now()
@@ -140,8 +167,8 @@ func TestRequestCompleteness(t *testing.T) {
 		Concurrency: 10,
 	}
 
-	mockClient := mocks.NewMockHTTPClientWithMetrics(results)
-	loadTester := usecases.NewLoadTesterUseCase(mockClient, usecases.NewReporter())
+	mockClient := mocks.NewMockExecutorWithMetrics(results)
+	loadTester := usecases.NewLoadTesterUseCase(mockClient, usecases.NewReporter(), observer.NewNoop())
 
 	report, err := loadTester.Execute(config)
 	if err != nil {
@@ -168,3 +195,75 @@ func TestRequestCompleteness(t *testing.T) {
 			totalResults, config.Requests)
 	}
 }
+
+func TestDynamicWorkDistribution(t *testing.T) {
+	var mu sync.Mutex
+	perWorker := make(map[int]int)
+
+	// o worker 0 responde dez vezes mais rapido que os demais
+	executor := domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+		delay := 20 * time.Millisecond
+		if req.WorkerID == 0 {
+			delay = 2 * time.Millisecond
+		}
+		time.Sleep(delay)
+
+		mu.Lock()
+		perWorker[req.WorkerID]++
+		mu.Unlock()
+		return &domain.TestResult{Duration: delay, Status: 200}, nil
+	})
+
+	loadTester := usecases.NewLoadTesterUseCase(executor, usecases.NewReporter(), observer.NewNoop())
+	report, err := loadTester.Execute(domain.TestConfig{URL: "http://test.com", Requests: 60, Concurrency: 3})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if report.TotalRequests != 60 {
+		t.Errorf("Total de requests incorreto: got %d, want 60", report.TotalRequests)
+	}
+	if perWorker[0] <= perWorker[1]+perWorker[2] {
+		t.Errorf("O worker mais rápido deveria enviar mais requests: got %v", perWorker)
+	}
+}
+
+func TestProgressTrackerWithMoreWorkersThanRequests(t *testing.T) {
+	config := domain.TestConfig{URL: "http://test.com", Requests: 2, Concurrency: 8}
+
+	tracker := progress.NewProgressTracker(io.Discard, false)
+	loadTester := usecases.NewLoadTesterUseCase(mocks.NewMockExecutorWithMetrics([]domain.TestResult{
+		{Duration: time.Millisecond, Status: 200},
+	}), usecases.NewReporter(), tracker)
+
+	report, err := loadTester.Execute(config)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if report.TotalRequests != 2 {
+		t.Errorf("Total de requests incorreto: got %d, want 2", report.TotalRequests)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	config := domain.TestConfig{URL: "http://test.com", Requests: 20, Concurrency: 5, Rate: 100}
+
+	mockClient := mocks.NewMockExecutorWithMetrics([]domain.TestResult{
+		{Duration: time.Millisecond, Status: 200},
+	})
+	loadTester := usecases.NewLoadTesterUseCase(mockClient, usecases.NewReporter(), observer.NewNoop())
+
+	start := time.Now()
+	report, err := loadTester.Execute(config)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	// 20 requests a 100/s precisam de ao menos 20 ticks de 10ms, independente da concorrência
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("Limite de taxa não respeitado: 20 requests em %v", elapsed)
+	}
+	if report.TotalRequests != 20 {
+		t.Errorf("Total de requests incorreto: got %d, want 20", report.TotalRequests)
+	}
+}
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/pkg/stress"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// corpo altamente compressivel devolvido pelo servidor de teste
+var compressiblePayload = strings.Repeat("stress-test ", 1000)
+
+// servidor que valida o corpo comprimido recebido e responde com a primeira codificacao aceita
+func newCompressionServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, err := decodeRequestBody(r)
+			if err != nil || string(body) != "payload" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+
+		encoding := strings.TrimSpace(strings.Split(r.Header.Get("Accept-Encoding"), ",")[0])
+		var out io.WriteCloser
+		switch encoding {
+		case "gzip":
+			out = gzip.NewWriter(w)
+		case "br":
+			out = brotli.NewWriter(w)
+		case "zstd":
+			out, _ = zstd.NewWriter(w)
+		default:
+			io.WriteString(w, compressiblePayload)
+			return
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		io.WriteString(out, compressiblePayload)
+		out.Close()
+	}))
+}
+
+func decodeRequestBody(r *http.Request) ([]byte, error) {
+	switch r.Header.Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(gz)
+	case "br":
+		return io.ReadAll(brotli.NewReader(r.Body))
+	case "zstd":
+		dec, err := zstd.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer dec.Close()
+		return io.ReadAll(dec)
+	}
+	return io.ReadAll(r.Body)
+}
+
+func TestCompression(t *testing.T) {
+	server := newCompressionServer()
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		bodyEncoding   string
+		wantEncoding   string
+	}{
+		{name: "Gzip por padrão", wantEncoding: "gzip"},
+		{name: "Brotli", acceptEncoding: "br", bodyEncoding: "br", wantEncoding: "br"},
+		{name: "Zstd", acceptEncoding: "zstd", bodyEncoding: "zstd", wantEncoding: "zstd"},
+		{name: "Corpo gzip", bodyEncoding: "gzip", wantEncoding: "gzip"},
+		{name: "Sem Accept-Encoding", acceptEncoding: "none", wantEncoding: "identity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.AcceptEncoding = tt.acceptEncoding
+			if tt.bodyEncoding != "" {
+				opts.Method = http.MethodPost
+				opts.Body = []byte("payload")
+				opts.BodyEncoding = tt.bodyEncoding
+			}
+
+			report, err := stress.Run(context.Background(), server.URL,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.SuccessRequests != 10 {
+				t.Fatalf("Requests com sucesso incorretas: got %d, want 10 (status: %v, erros: %v)", report.SuccessRequests, report.StatusDistrib, report.ErrorsByClass)
+			}
+			if report.AttributeDistrib[domain.AttributeContentEncoding][tt.wantEncoding] != 10 {
+				t.Errorf("Distribuição de codificação incorreta: got %v, want 10x %s", report.AttributeDistrib[domain.AttributeContentEncoding], tt.wantEncoding)
+			}
+			if report.DecodedBytes != int64(10*len(compressiblePayload)) {
+				t.Errorf("Bytes descomprimidos incorretos: got %d, want %d", report.DecodedBytes, 10*len(compressiblePayload))
+			}
+
+			compressed := tt.wantEncoding != "identity"
+			if compressed && report.CompressionRatio <= 10 {
+				t.Errorf("Razão de compressão muito baixa: %.2f (wire %d bytes)", report.CompressionRatio, report.WireBytes)
+			}
+			if !compressed && report.CompressionRatio != 1 {
+				t.Errorf("Razão de compressão deveria ser 1 sem compressão: %.2f", report.CompressionRatio)
+			}
+		})
+	}
+}
+
+func TestCompressionInvalidResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(bytes.Repeat([]byte("x"), 100))
+	}))
+	defer server.Close()
+
+	report, err := stress.Run(context.Background(), server.URL, stress.WithRequests(5))
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if report.ErrorsByClass["decode"] != 5 {
+		t.Errorf("Erros de decodificação incorretos: got %v, want 5", report.ErrorsByClass)
+	}
+}
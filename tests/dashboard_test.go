@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"bytes"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/progress"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// alimenta o dashboard com um teste de 10 requests, 2 workers e 8 resultados:
+// 6 respostas 200, uma 500 e um erro, com duracoes de 1ms a 8ms
+func feedDashboard(dashboard *progress.Dashboard) {
+	dashboard.OnEvent(domain.Event{Type: domain.EventStarted, Config: &domain.TestConfig{Requests: 10, Concurrency: 2, Rate: 50}})
+	dashboard.OnEvent(domain.Event{Type: domain.EventWorkerStarted, WorkerID: 0})
+	dashboard.OnEvent(domain.Event{Type: domain.EventWorkerStarted, WorkerID: 1})
+
+	for i := 1; i <= 8; i++ {
+		result := domain.TestResult{Duration: time.Duration(i) * time.Millisecond, Status: 200}
+		switch i {
+		case 7:
+			result.Status = 500
+		case 8:
+			result.Status = 0
+			result.Error = domain.ErrTimeout
+		}
+		dashboard.OnEvent(domain.Event{Type: domain.EventRequestDone, Result: &result})
+	}
+}
+
+func TestDashboardAggregation(t *testing.T) {
+	var out bytes.Buffer
+	dashboard := progress.NewDashboard(&out)
+	feedDashboard(dashboard)
+	defer dashboard.Stop()
+
+	stats := dashboard.Stats(time.Now())
+
+	if stats.Completed != 8 || stats.TotalRequests != 10 || stats.ActiveWorkers != 2 {
+		t.Errorf("Progresso incorreto: got %d/%d com %d workers, want 8/10 com 2", stats.Completed, stats.TotalRequests, stats.ActiveWorkers)
+	}
+	if stats.StatusCounts[200] != 6 || stats.StatusCounts[500] != 1 || stats.Errors != 1 {
+		t.Errorf("Contagem de status incorreta: got %v e %d erros", stats.StatusCounts, stats.Errors)
+	}
+	// a resposta 500 e o erro contam como falha
+	if stats.ErrorRate != 25 {
+		t.Errorf("Taxa de erros incorreta: got %.1f%%, want 25%%", stats.ErrorRate)
+	}
+	if stats.P50 != 4*time.Millisecond || stats.P95 != 8*time.Millisecond || stats.P99 != 8*time.Millisecond {
+		t.Errorf("Percentis incorretos: got p50 %v, p95 %v, p99 %v", stats.P50, stats.P95, stats.P99)
+	}
+	if stats.TargetRPS != 50 {
+		t.Errorf("RPS alvo incorreto: got %.0f, want 50", stats.TargetRPS)
+	}
+}
+
+// a taxa de erros do dashboard usa o mesmo criterio de sucesso do relatorio e da busca
+func TestDashboardErrorRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		want     float64
+	}{
+		{name: "respostas 2xx sao sucesso", statuses: []int{200, 201, 204, 299}, want: 0},
+		{name: "redirecionamentos e 4xx sao falha", statuses: []int{200, 302, 404, 204}, want: 50},
+		{name: "respostas 5xx sao falha", statuses: []int{500, 503, 200, 201}, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dashboard := progress.NewDashboard(&bytes.Buffer{})
+			defer dashboard.Stop()
+			dashboard.OnEvent(domain.Event{Type: domain.EventStarted, Config: &domain.TestConfig{Requests: len(tt.statuses), Concurrency: 1}})
+			for _, status := range tt.statuses {
+				result := domain.TestResult{Duration: time.Millisecond, Status: status}
+				dashboard.OnEvent(domain.Event{Type: domain.EventRequestDone, Result: &result})
+			}
+
+			if stats := dashboard.Stats(time.Now()); stats.ErrorRate != tt.want {
+				t.Errorf("Taxa de erros incorreta: got %.1f%%, want %.1f%%", stats.ErrorRate, tt.want)
+			}
+		})
+	}
+}
+
+func TestDashboardRollingWindow(t *testing.T) {
+	dashboard := progress.NewDashboard(&bytes.Buffer{})
+	feedDashboard(dashboard)
+	defer dashboard.Stop()
+
+	// 4s apos o inicio a janela ainda contem as 8 amostras: 2 req/s e 1s para as 2 restantes
+	stats := dashboard.Stats(time.Now().Add(4 * time.Second))
+	if math.Abs(stats.RPS-2) > 0.05 {
+		t.Errorf("RPS incorreto: got %.2f, want 2", stats.RPS)
+	}
+	if diff := stats.Remaining - time.Second; diff < -50*time.Millisecond || diff > 50*time.Millisecond {
+		t.Errorf("Tempo restante incorreto: got %v, want ~1s", stats.Remaining)
+	}
+
+	// depois da janela as amostras sao descartadas, mas os totais permanecem
+	stats = dashboard.Stats(time.Now().Add(11 * time.Second))
+	if stats.RPS != 0 || stats.P99 != 0 || stats.ErrorRate != 0 || stats.Remaining != 0 {
+		t.Errorf("Métricas fora da janela deveriam zerar: got RPS %.2f, p99 %v, erros %.1f%%, restante %v",
+			stats.RPS, stats.P99, stats.ErrorRate, stats.Remaining)
+	}
+	if stats.Completed != 8 || stats.StatusCounts[200] != 6 {
+		t.Errorf("Totais não deveriam depender da janela: got %d concluídas e %v", stats.Completed, stats.StatusCounts)
+	}
+}
+
+func TestDashboardRender(t *testing.T) {
+	var out bytes.Buffer
+	dashboard := progress.NewDashboard(&out)
+	feedDashboard(dashboard)
+	dashboard.OnEvent(domain.Event{Type: domain.EventFinished, Report: &domain.TestReport{}})
+
+	rendered := out.String()
+	for _, want := range []string{"Stress Tester - Dashboard", "8/10", "alvo: 50", "p50 4ms", "200: 6 | 500: 1 | erros: 1"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("Painel deveria conter %q, got:\n%s", want, rendered)
+		}
+	}
+}
+
+func TestDashboardReuse(t *testing.T) {
+	dashboard := progress.NewDashboard(&bytes.Buffer{})
+	feedDashboard(dashboard)
+	dashboard.OnEvent(domain.Event{Type: domain.EventFinished, Report: &domain.TestReport{}})
+
+	// uma segunda execucao comeca com as metricas zeradas
+	dashboard.OnEvent(domain.Event{Type: domain.EventStarted, Config: &domain.TestConfig{Requests: 5, Concurrency: 1}})
+	dashboard.OnEvent(domain.Event{Type: domain.EventWorkerStarted, WorkerID: 0})
+	dashboard.OnEvent(domain.Event{Type: domain.EventRequestDone, Result: &domain.TestResult{Duration: time.Millisecond, Status: 201}})
+	defer dashboard.Stop()
+
+	stats := dashboard.Stats(time.Now())
+	if stats.Completed != 1 || stats.TotalRequests != 5 || stats.ActiveWorkers != 1 {
+		t.Errorf("Progresso da segunda execução incorreto: got %d/%d com %d workers, want 1/5 com 1", stats.Completed, stats.TotalRequests, stats.ActiveWorkers)
+	}
+	if len(stats.StatusCounts) != 1 || stats.StatusCounts[201] != 1 || stats.Errors != 0 {
+		t.Errorf("Status da execução anterior não foram descartados: got %v e %d erros", stats.StatusCounts, stats.Errors)
+	}
+	if stats.TargetRPS != 0 || stats.ErrorRate != 0 {
+		t.Errorf("Métricas da execução anterior não foram descartadas: got alvo %.0f e erros %.1f%%", stats.TargetRPS, stats.ErrorRate)
+	}
+}
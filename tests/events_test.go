@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/observer"
+	"go-expert-stress-test/tests/mocks"
+	"go-expert-stress-test/usecases"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLifecycleEvents(t *testing.T) {
+	config := domain.TestConfig{
+		URL:         "http://test.com",
+		Requests:    30,
+		Concurrency: 3,
+	}
+
+	var mu sync.Mutex
+	var events []domain.Event
+	subscriber := domain.ObserverFunc(func(event domain.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+
+	mockClient := mocks.NewMockExecutorWithMetrics([]domain.TestResult{
+		{Duration: 10 * time.Millisecond, Status: 200},
+	})
+	loadTester := usecases.NewLoadTesterUseCase(mockClient, usecases.NewReporter(), subscriber)
+
+	report, err := loadTester.Execute(config)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	counts := make(map[domain.EventType]int)
+	var stages []string
+	for _, event := range events {
+		counts[event.Type]++
+		if event.Type == domain.EventStageChanged {
+			stages = append(stages, event.Stage)
+		}
+	}
+
+	if first := events[0]; first.Type != domain.EventStarted || first.Config.Requests != config.Requests {
+		t.Errorf("Primeiro evento deveria ser started com a configuração do teste: got %+v", first)
+	}
+	if last := events[len(events)-1]; last.Type != domain.EventFinished || last.Report != report {
+		t.Errorf("Último evento deveria ser finished com o relatório: got %+v", last)
+	}
+	if counts[domain.EventRequestDone] != config.Requests {
+		t.Errorf("Eventos request_done incorretos: got %d, want %d", counts[domain.EventRequestDone], config.Requests)
+	}
+	if counts[domain.EventWorkerStarted] != config.Concurrency || counts[domain.EventWorkerFinished] != config.Concurrency {
+		t.Errorf("Eventos de worker incorretos: got %d iniciados e %d finalizados, want %d",
+			counts[domain.EventWorkerStarted], counts[domain.EventWorkerFinished], config.Concurrency)
+	}
+	if len(stages) != 2 || stages[0] != domain.StageRunning || stages[1] != domain.StageReporting {
+		t.Errorf("Etapas incorretas: got %v", stages)
+	}
+}
+
+// o worker_finished de cada worker so chega depois de todos os request_done dele, para que
+// observers que congelam o progresso do worker nao percam as ultimas requisicoes
+func TestWorkerFinishedAfterRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		config domain.TestConfig
+	}{
+		{name: "um worker", config: domain.TestConfig{URL: "http://test.com", Requests: 200, Concurrency: 1}},
+		{name: "varios workers", config: domain.TestConfig{URL: "http://test.com", Requests: 500, Concurrency: 8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			finished := make(map[int]bool)
+			var late, done int
+			subscriber := domain.ObserverFunc(func(event domain.Event) {
+				mu.Lock()
+				defer mu.Unlock()
+				switch event.Type {
+				case domain.EventWorkerFinished:
+					finished[event.WorkerID] = true
+				case domain.EventRequestDone:
+					done++
+					if finished[event.WorkerID] {
+						late++
+					}
+				}
+			})
+
+			// executor instantaneo, para que os workers terminem antes do coletor ler seus resultados
+			executor := domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+				return &domain.TestResult{Status: 200}, nil
+			})
+			loadTester := usecases.NewLoadTesterUseCase(executor, usecases.NewReporter(), subscriber)
+			if _, err := loadTester.Execute(tt.config); err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if late != 0 {
+				t.Errorf("Eventos request_done depois do worker_finished do worker: got %d, want 0", late)
+			}
+			if done != tt.config.Requests || len(finished) != tt.config.Concurrency {
+				t.Errorf("Eventos incorretos: got %d request_done e %d workers finalizados, want %d e %d",
+					done, len(finished), tt.config.Requests, tt.config.Concurrency)
+			}
+		})
+	}
+}
+
+// writer que falha a partir da segunda escrita
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes > 1 {
+		return 0, fmt.Errorf("disk full on write %d", w.writes)
+	}
+	return len(p), nil
+}
+
+func TestJSONLinesWriteError(t *testing.T) {
+	w := &failingWriter{}
+	events := observer.NewJSONLines(w)
+
+	events.OnEvent(domain.Event{Type: domain.EventStageChanged, Stage: domain.StageRunning})
+	if err := events.Err(); err != nil {
+		t.Fatalf("Erro inesperado após escrita bem sucedida: %v", err)
+	}
+
+	events.OnEvent(domain.Event{Type: domain.EventStageChanged, Stage: domain.StageReporting})
+	events.OnEvent(domain.Event{Type: domain.EventStageChanged, Stage: domain.StageReporting})
+
+	// o primeiro erro e preservado e as escritas seguintes nao sao tentadas
+	if err := events.Err(); err == nil || err.Error() != "disk full on write 2" {
+		t.Errorf("Erro de escrita incorreto: got %v, want disk full on write 2", err)
+	}
+	if w.writes != 2 {
+		t.Errorf("Escritas após a falha: got %d, want 2", w.writes)
+	}
+}
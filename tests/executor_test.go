@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/observer"
+	"go-expert-stress-test/tests/mocks"
+	"go-expert-stress-test/usecases"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExecutorErrorsAreReported(t *testing.T) {
+	errBroken := errors.New("executor broken")
+	var calls atomic.Int64
+
+	// a cada 4 requisicoes uma falha no proprio executor, sem resultado
+	executor := domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+		if calls.Add(1)%4 == 0 {
+			return nil, errBroken
+		}
+		return &domain.TestResult{Duration: time.Millisecond, Status: 200}, nil
+	})
+
+	loadTester := usecases.NewLoadTesterUseCase(executor, usecases.NewReporter(), observer.NewNoop())
+	report, err := loadTester.Execute(domain.TestConfig{URL: "custom://alvo", Requests: 40, Concurrency: 4})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if report.TotalRequests != 40 {
+		t.Errorf("Requests com falha do executor não deveriam ser descartadas: got %d, want 40", report.TotalRequests)
+	}
+	if report.ErrorCount != 10 || report.SuccessRequests != 30 {
+		t.Errorf("Resultado incorreto: %d erros e %d sucessos, want 10 e 30", report.ErrorCount, report.SuccessRequests)
+	}
+}
+
+func TestCustomProtocolAttributes(t *testing.T) {
+	var mu sync.Mutex
+	workers := make(map[int]bool)
+	executor := domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+		mu.Lock()
+		workers[req.WorkerID] = true
+		mu.Unlock()
+
+		code := "HIT"
+		if req.WorkerID == 0 {
+			code = "MISS"
+		}
+		return &domain.TestResult{
+			Duration:   time.Millisecond,
+			Status:     200,
+			Protocol:   "cache",
+			Attributes: map[string]string{"cache.result": code},
+		}, nil
+	})
+
+	// a barreira garante que os dois workers retirem requisicoes da fila
+	barrier := mocks.NewBarrierExecutor(executor)
+	loadTester := usecases.NewLoadTesterUseCase(barrier, usecases.NewReporter(), barrier)
+	report, err := loadTester.Execute(domain.TestConfig{URL: "cache://alvo", Requests: 20, Concurrency: 2})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if report.ProtocolDistrib["cache"] != 20 {
+		t.Errorf("Protocolo incorreto: got %v", report.ProtocolDistrib)
+	}
+	results := report.AttributeDistrib["cache.result"]
+	if results["HIT"]+results["MISS"] != 20 || results["MISS"] == 0 {
+		t.Errorf("Atributos agregados incorretamente: got %v", report.AttributeDistrib)
+	}
+	if len(workers) != 2 {
+		t.Errorf("Requisições deveriam informar o worker: got %d workers, want 2", len(workers))
+	}
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/pkg/stress"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// servidor GraphQL simplificado: responde erros de acordo com a variavel id recebida
+func newGraphQLServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query         string         `json:"query"`
+			Variables     map[string]any `json:"variables"`
+			OperationName string         `json:"operationName"`
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Query == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Variables["id"] {
+		case "missing":
+			w.Write([]byte(`{"data":{"order":null},"errors":[{"message":"order not found","path":["order"]}]}`))
+		case "forbidden":
+			w.Write([]byte(`{"data":null,"errors":[{"message":"forbidden"},{"message":"order not found"}]}`))
+		default:
+			w.Write([]byte(`{"data":{"order":{"id":"42"}}}`))
+		}
+	}))
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	server := newGraphQLServer()
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		variables     string
+		success       int
+		graphqlErrors map[string]int
+	}{
+		{name: "Resposta sem erros", variables: `{"id":"42"}`, success: 10},
+		{name: "200 com erros conta como falha", variables: `{"id":"missing"}`, graphqlErrors: map[string]int{"order not found": 10}},
+		{name: "Mensagens agrupadas", variables: `{"id":"forbidden"}`, graphqlErrors: map[string]int{"forbidden": 10, "order not found": 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.GraphQL = httpclient.GraphQLOptions{
+				Query:     `query Order($id: ID!) { order(id: $id) { id } }`,
+				Variables: json.RawMessage(tt.variables),
+			}
+
+			report, err := stress.Run(context.Background(), server.URL,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.SuccessRequests != tt.success {
+				t.Errorf("Requests com sucesso incorretas: got %d, want %d", report.SuccessRequests, tt.success)
+			}
+			if report.ErrorsByClass["graphql"] != 10-tt.success {
+				t.Errorf("Erros GraphQL incorretos: got %v, want %d", report.ErrorsByClass, 10-tt.success)
+			}
+			for message, count := range tt.graphqlErrors {
+				if report.GraphQLErrors[message] != count {
+					t.Errorf("Mensagens agrupadas incorretas: got %v, want %v", report.GraphQLErrors, tt.graphqlErrors)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestGraphQLInvalidVariables(t *testing.T) {
+	opts := httpclient.DefaultOptions(1)
+	opts.GraphQL = httpclient.GraphQLOptions{Query: "{ orders { id } }", Variables: json.RawMessage(`[1, 2]`)}
+
+	_, err := stress.Run(context.Background(), "http://localhost", stress.WithClientOptions(opts))
+	if !errors.Is(err, stress.ErrInvalidConfig) {
+		t.Errorf("Esperava ErrInvalidConfig, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/grpcclient"
+	"go-expert-stress-test/pkg/stress"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// servidor gRPC com o servico de health check e reflection habilitada
+func newGRPCServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Erro ao abrir listener: %v", err)
+	}
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return "grpc://" + listener.Addr().String()
+}
+
+// grava o descriptor set do servico de health check, como o gerado pelo protoc
+func writeHealthDescriptorSet(t *testing.T) string {
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(healthpb.File_grpc_health_v1_health_proto)},
+	}
+	content, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatalf("Erro ao serializar descriptor set: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "health.protoset")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("Erro ao gravar descriptor set: %v", err)
+	}
+	return path
+}
+
+func TestGRPCUnary(t *testing.T) {
+	target := newGRPCServer(t)
+	descriptorSet := writeHealthDescriptorSet(t)
+
+	tests := []struct {
+		name          string
+		request       string
+		descriptorSet string
+		status        int
+		grpcStatus    string
+	}{
+		{name: "Reflection", request: `{"service":"orders"}`, status: 200, grpcStatus: "OK"},
+		{name: "Descriptor set", request: `{"service":"orders"}`, descriptorSet: descriptorSet, status: 200, grpcStatus: "OK"},
+		{name: "Código gRPC mapeado para HTTP", request: `{"service":"payments"}`, status: 404, grpcStatus: "NotFound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := stress.Run(context.Background(), target,
+				stress.WithRequests(20),
+				stress.WithConcurrency(4),
+				stress.WithGRPCOptions(grpcclient.Options{
+					Method:        "grpc.health.v1.Health/Check",
+					Request:       tt.request,
+					DescriptorSet: tt.descriptorSet,
+				}),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.StatusDistrib[tt.status] != 20 {
+				t.Errorf("Distribuição de status incorreta: got %v, want 20x %d", report.StatusDistrib, tt.status)
+			}
+			if report.AttributeDistrib["grpc.status"][tt.grpcStatus] != 20 {
+				t.Errorf("Distribuição de status gRPC incorreta: got %v, want 20x %s", report.AttributeDistrib, tt.grpcStatus)
+			}
+			if report.ProtocolDistrib["grpc"] != 20 {
+				t.Errorf("Protocolo incorreto: got %v", report.ProtocolDistrib)
+			}
+		})
+	}
+}
+
+func TestGRPCErrors(t *testing.T) {
+	descriptorSet := writeHealthDescriptorSet(t)
+
+	// porta sem servidor para simular o servico indisponivel
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Erro ao abrir listener: %v", err)
+	}
+	closedTarget := "grpc://" + listener.Addr().String()
+	listener.Close()
+
+	tests := []struct {
+		name       string
+		target     string
+		timeout    time.Duration
+		class      string
+		grpcStatus string
+		httpStatus int
+	}{
+		{name: "DeadlineExceeded conta como timeout", target: newGRPCServer(t), timeout: time.Nanosecond, class: "timeout", grpcStatus: "DeadlineExceeded", httpStatus: 504},
+		{name: "Unavailable conta como erro de conexão", target: closedTarget, class: "connection", grpcStatus: "Unavailable", httpStatus: 503},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := stress.Run(context.Background(), tt.target,
+				stress.WithRequests(3),
+				stress.WithGRPCOptions(grpcclient.Options{
+					Method:        "grpc.health.v1.Health/Check",
+					DescriptorSet: descriptorSet,
+					Timeout:       tt.timeout,
+				}),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.ErrorsByClass[tt.class] != 3 {
+				t.Errorf("Classe de erro incorreta: got %v, want 3 %s", report.ErrorsByClass, tt.class)
+			}
+			// os erros continuam aparecendo nas distribuicoes de status
+			if report.AttributeDistrib["grpc.status"][tt.grpcStatus] != 3 {
+				t.Errorf("Status gRPC incorreto: got %v, want 3 %s", report.AttributeDistrib["grpc.status"], tt.grpcStatus)
+			}
+			if report.StatusDistrib[tt.httpStatus] != 3 {
+				t.Errorf("Status HTTP incorreto: got %v, want 3 %d", report.StatusDistrib, tt.httpStatus)
+			}
+		})
+	}
+}
+
+func TestGRPCCanceled(t *testing.T) {
+	client, err := grpcclient.NewClient(newGRPCServer(t), grpcclient.Options{
+		Method:        "grpc.health.v1.Health/Check",
+		DescriptorSet: writeHealthDescriptorSet(t),
+	})
+	if err != nil {
+		t.Fatalf("Erro ao criar cliente: %v", err)
+	}
+	defer client.Close()
+
+	// chamadas interrompidas pelo fim do teste retornam o erro do contexto e sao descartadas
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := client.Execute(ctx, domain.Request{})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if !errors.Is(result.Error, context.Canceled) || result.Attributes["grpc.status"] != "Canceled" {
+		t.Errorf("Chamada cancelada deveria retornar o erro do contexto: got %v (%v)", result.Error, result.Attributes)
+	}
+}
+
+func TestGRPCInvalidConfig(t *testing.T) {
+	target := newGRPCServer(t)
+
+	tests := []struct {
+		name string
+		opts grpcclient.Options
+	}{
+		{name: "Método sem serviço", opts: grpcclient.Options{Method: "Check"}},
+		{name: "Serviço desconhecido", opts: grpcclient.Options{Method: "orders.Orders/Get"}},
+		{name: "Método desconhecido", opts: grpcclient.Options{Method: "grpc.health.v1.Health/Ping"}},
+		{name: "Método streaming", opts: grpcclient.Options{Method: "grpc.health.v1.Health/Watch"}},
+		{name: "JSON inválido", opts: grpcclient.Options{Method: "grpc.health.v1.Health/Check", Request: `{"unknown":1}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := stress.Run(context.Background(), target, stress.WithGRPCOptions(tt.opts))
+			if !errors.Is(err, stress.ErrInvalidConfig) {
+				t.Errorf("Esperava ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
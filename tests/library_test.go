@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/pkg/stress"
+	"go-expert-stress-test/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLibraryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var requestEvents atomic.Int64
+	report, err := stress.Run(context.Background(), server.URL,
+		stress.WithRequests(40),
+		stress.WithConcurrency(4),
+		stress.WithEventHandler(func(event stress.Event) {
+			if event.Type == domain.EventRequestDone {
+				requestEvents.Add(1)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if report.TotalRequests != 40 || report.SuccessRequests != 40 {
+		t.Errorf("Relatório incorreto: got %d requests e %d sucessos, want 40", report.TotalRequests, report.SuccessRequests)
+	}
+	if requestEvents.Load() != 40 {
+		t.Errorf("Eventos request_done incorretos: got %d, want 40", requestEvents.Load())
+	}
+}
+
+func TestLibraryCancel(t *testing.T) {
+	mockClient := mocks.NewMockExecutorWithMetrics([]domain.TestResult{
+		{Duration: 10 * time.Millisecond, Status: 200},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	report, err := stress.Run(ctx, "http://test.com",
+		stress.WithRequests(1000),
+		stress.WithConcurrency(2),
+		stress.WithExecutor(mockClient),
+	)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Erro esperado de contexto: got %v", err)
+	}
+	if report == nil || report.TotalRequests == 0 || report.TotalRequests >= 1000 {
+		t.Errorf("Relatório parcial esperado após cancelamento: got %+v", report)
+	}
+}
+
+func TestLibraryInvalidConfig(t *testing.T) {
+	_, err := stress.Run(context.Background(), "http://test.com", stress.WithConcurrency(0))
+	if !errors.Is(err, stress.ErrInvalidConfig) {
+		t.Errorf("Erro de configuração esperado: got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/metrics"
+	"go-expert-stress-test/tests/mocks"
+	"go-expert-stress-test/usecases"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	config := domain.TestConfig{
+		URL:         "http://test.com",
+		Requests:    20,
+		Concurrency: 4,
+	}
+
+	mockClient := mocks.NewMockExecutorWithMetrics([]domain.TestResult{
+		{Duration: 20 * time.Millisecond, Status: 200},
+		{Duration: 20 * time.Millisecond, Status: 500},
+		{Duration: 3 * time.Second, Error: domain.ErrTimeout},
+		{Duration: 20 * time.Millisecond, Error: domain.ErrConnection},
+	})
+
+	collector := metrics.NewPrometheusCollector()
+	loadTester := usecases.NewLoadTesterUseCase(mockClient, usecases.NewReporter(), collector)
+
+	if _, err := loadTester.Execute(config); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	var out strings.Builder
+	if err := collector.WriteMetrics(&out); err != nil {
+		t.Fatalf("Erro ao escrever métricas: %v", err)
+	}
+
+	expected := []string{
+		`stress_requests_total{status="200"} 5`,
+		`stress_requests_total{status="500"} 5`,
+		`stress_errors_total{class="timeout"} 5`,
+		`stress_errors_total{class="connection"} 5`,
+		`stress_request_duration_seconds_bucket{le="0.025"} 15`,
+		`stress_request_duration_seconds_bucket{le="+Inf"} 20`,
+		`stress_request_duration_seconds_count 20`,
+		`stress_active_workers 0`,
+		`stress_target_rps 0`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Métrica ausente: %s\n%s", line, out.String())
+		}
+	}
+}
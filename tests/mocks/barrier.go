@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"context"
+	"go-expert-stress-test/domain"
+	"sync"
+)
+
+// executor que segura a primeira requisicao de cada worker ate todos os workers do teste terem
+// retirado a sua, garantindo que cada worker envie ao menos uma requisicao sem depender de
+// sleeps. Tambem e o observer do teste: o evento started informa quantos workers esperar
+type BarrierExecutor struct {
+	next domain.Executor
+
+	mu      sync.Mutex
+	workers int
+	arrived map[int]bool
+	release chan struct{}
+}
+
+// cria a barreira em volta de next; deve ser registrada tambem como observer do teste
+func NewBarrierExecutor(next domain.Executor) *BarrierExecutor {
+	return &BarrierExecutor{next: next}
+}
+
+// reinicia a barreira a cada teste (ou degrau da busca) com o numero de workers que o load
+// tester inicia
+func (b *BarrierExecutor) OnEvent(event domain.Event) {
+	if event.Type != domain.EventStarted {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.workers = min(event.Config.Concurrency, event.Config.Requests)
+	b.arrived = make(map[int]bool)
+	b.release = make(chan struct{})
+}
+
+func (b *BarrierExecutor) Execute(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+	b.mu.Lock()
+	first := !b.arrived[req.WorkerID]
+	b.arrived[req.WorkerID] = true
+	release := b.release
+	if first && len(b.arrived) == b.workers {
+		close(release)
+	}
+	b.mu.Unlock()
+
+	if first {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return b.next.Execute(ctx, req)
+}
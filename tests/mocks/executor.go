@@ -1,13 +1,14 @@
 package mocks
 
 import (
+	"context"
 	"go-expert-stress-test/domain"
 	"sync"
 	"time"
 )
 
 // armazena metricas detalhadas das chamadas
-type MockHTTPClientWithMetrics struct {
+type MockExecutorWithMetrics struct {
 	mu                sync.Mutex
 	calls             []time.Time
 	activeConnections int
@@ -16,21 +17,21 @@ type MockHTTPClientWithMetrics struct {
 	results           []domain.TestResult
 }
 
-// cria um mock do cliente http com os resultados
-func NewMockHTTPClientWithMetrics(results []domain.TestResult) *MockHTTPClientWithMetrics {
-	return &MockHTTPClientWithMetrics{
+// cria um mock do executor com os resultados
+func NewMockExecutorWithMetrics(results []domain.TestResult) *MockExecutorWithMetrics {
+	return &MockExecutorWithMetrics{
 		calls:      make([]time.Time, 0),
 		uniqueURLs: make(map[string]struct{}),
 		results:    results,
 	}
 }
 
-func (m *MockHTTPClientWithMetrics) Get(url string) (*domain.TestResult, error) {
+func (m *MockExecutorWithMetrics) Execute(_ context.Context, req domain.Request) (*domain.TestResult, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	m.calls = append(m.calls, time.Now())
-	m.uniqueURLs[url] = struct{}{}
+	m.uniqueURLs[req.Target] = struct{}{}
 
 	m.activeConnections++
 	if m.activeConnections > m.maxConnections {
@@ -46,7 +47,7 @@ func (m *MockHTTPClientWithMetrics) Get(url string) (*domain.TestResult, error)
 }
 
 // retorna 3 valores conhecidos, numero de chamadas, numero de conxoes e url unicas
-func (m *MockHTTPClientWithMetrics) GetMetrics() (totalCalls int, maxConcurrency int, uniqueURLs int) {
+func (m *MockExecutorWithMetrics) GetMetrics() (totalCalls int, maxConcurrency int, uniqueURLs int) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
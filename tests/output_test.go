@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"bytes"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/observer"
+	"go-expert-stress-test/infra/progress"
+	"go-expert-stress-test/interfaces/cli"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleReport() *domain.TestReport {
+	return &domain.TestReport{
+		TotalDuration:   2 * time.Second,
+		TotalRequests:   10,
+		SuccessRequests: 9,
+		StatusDistrib:   map[int]int{200: 9, 500: 1},
+		ErrorCount:      0,
+		AverageDuration: 15 * time.Millisecond,
+		ConnsOpened:     2,
+		ConnsReused:     8,
+	}
+}
+
+func TestOutputOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		interactive bool
+		noColor     bool
+		noColorEnv  bool
+		quiet       bool
+		wantColor   bool
+	}{
+		{name: "Terminal com cores", interactive: true, wantColor: true},
+		{name: "Saída redirecionada", interactive: false},
+		{name: "Flag --no-color", interactive: true, noColor: true},
+		{name: "Variável NO_COLOR", interactive: true, noColorEnv: true},
+		{name: "Modo silencioso", interactive: true, quiet: true, wantColor: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.noColorEnv {
+				t.Setenv("NO_COLOR", "1")
+			} else {
+				os.Unsetenv("NO_COLOR")
+			}
+
+			output := cli.NewOutputOptions(tt.interactive, tt.noColor, tt.quiet)
+			if output.Color != tt.wantColor || output.Interactive != tt.interactive || output.Quiet != tt.quiet {
+				t.Errorf("Opções incorretas: got %+v, want cor %v", output, tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestProgressObserverSelection(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    cli.OutputOptions
+		dashboard bool
+		want      string
+	}{
+		{name: "Silencioso", output: cli.OutputOptions{Interactive: true, Quiet: true}, want: "observer.Noop"},
+		{name: "Sem terminal", output: cli.OutputOptions{}, want: "*progress.StatusLine"},
+		{name: "Dashboard", output: cli.OutputOptions{Interactive: true}, dashboard: true, want: "*progress.Dashboard"},
+		{name: "Barras por worker", output: cli.OutputOptions{Interactive: true}, want: "*progress.Tracker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			switch cli.NewProgressObserver(tt.output, tt.dashboard).(type) {
+			case observer.Noop:
+				got = "observer.Noop"
+			case *progress.StatusLine:
+				got = "*progress.StatusLine"
+			case *progress.Dashboard:
+				got = "*progress.Dashboard"
+			case *progress.Tracker:
+				got = "*progress.Tracker"
+			}
+			if got != tt.want {
+				t.Errorf("Observer incorreto: got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusLine(t *testing.T) {
+	var out bytes.Buffer
+	status := progress.NewStatusLine(&out, time.Hour)
+
+	status.OnEvent(domain.Event{Type: domain.EventStarted, Config: &domain.TestConfig{Requests: 4}})
+	for i := 0; i < 3; i++ {
+		status.OnEvent(domain.Event{Type: domain.EventRequestDone, Result: &domain.TestResult{Status: 200}})
+	}
+	status.OnEvent(domain.Event{Type: domain.EventFinished, Report: &domain.TestReport{}})
+
+	// sem ticks, apenas a linha final e impressa, em texto puro
+	line := out.String()
+	if !regexp.MustCompile(`^\[\d\d:\d\d\] 3/4 requests \(75\.0%\) \| [\d.]+ req/s\n$`).MatchString(line) {
+		t.Errorf("Linha de status incorreta: %q", line)
+	}
+}
+
+func TestProgressReuse(t *testing.T) {
+	run := func(o domain.ProgressObserver, requests int) {
+		o.OnEvent(domain.Event{Type: domain.EventStarted, Config: &domain.TestConfig{Requests: requests, Concurrency: 1}})
+		o.OnEvent(domain.Event{Type: domain.EventWorkerStarted, WorkerID: 0})
+		for i := 0; i < requests; i++ {
+			o.OnEvent(domain.Event{Type: domain.EventRequestDone, WorkerID: 0, Result: &domain.TestResult{Status: 200}})
+		}
+		o.OnEvent(domain.Event{Type: domain.EventWorkerFinished, WorkerID: 0})
+		o.OnEvent(domain.Event{Type: domain.EventFinished, Report: &domain.TestReport{}})
+	}
+
+	t.Run("StatusLine reinicia a contagem", func(t *testing.T) {
+		var out bytes.Buffer
+		status := progress.NewStatusLine(&out, time.Hour)
+		run(status, 3)
+		run(status, 2)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 2 || !strings.Contains(lines[0], " 3/3 ") || !strings.Contains(lines[1], " 2/2 ") {
+			t.Errorf("Linhas de status incorretas: %q", lines)
+		}
+	})
+
+	t.Run("Tracker desenha barras novas", func(t *testing.T) {
+		var out bytes.Buffer
+		tracker := progress.NewProgressTracker(&out, false)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			run(tracker, 3)
+			run(tracker, 2)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Tracker não encerrou a segunda execução")
+		}
+		if strings.Count(out.String(), "Total Progress") < 2 {
+			t.Errorf("Barras da segunda execução não foram desenhadas: %q", out.String())
+		}
+	})
+}
+
+func TestQuietSummary(t *testing.T) {
+	var out bytes.Buffer
+	cli.NewReportPresenter(&out, cli.OutputOptions{Quiet: true}).Present(sampleReport())
+
+	want := "duration=2s requests=10 ok=9 errors=0 avg=15ms conns_opened=2 conns_reused=8 status=[200=9 500=1]\n"
+	if out.String() != want {
+		t.Errorf("Resumo silencioso incorreto:\ngot  %q\nwant %q", out.String(), want)
+	}
+}
+
+func TestNoColorOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		color    bool
+		wantANSI bool
+	}{
+		{name: "Sem cores", color: false},
+		{name: "Com cores", color: true, wantANSI: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cli.NewReportPresenter(&buf, cli.OutputOptions{Color: tt.color}).Present(sampleReport())
+			out := buf.String()
+
+			if strings.Contains(out, "\033[") != tt.wantANSI {
+				t.Errorf("Sequências ANSI na saída: got %v, want %v", strings.Contains(out, "\033["), tt.wantANSI)
+			}
+			if !strings.Contains(out, "Total Requests:") {
+				t.Errorf("Relatório incompleto: %q", out)
+			}
+		})
+	}
+}
+
+func TestAttributeSections(t *testing.T) {
+	report := sampleReport()
+	report.WireBytes = 100
+	report.DecodedBytes = 400
+	report.TLSHandshakes = 2
+	report.AttributeDistrib = map[string]map[string]int{
+		domain.AttributeTLS:             {"TLS 1.3 TLS_AES_128_GCM_SHA256": 10},
+		domain.AttributeContentEncoding: {"gzip": 10},
+		"grpc.status":                   {"OK": 10},
+	}
+
+	var buf bytes.Buffer
+	cli.NewReportPresenter(&buf, cli.OutputOptions{}).Present(report)
+	out := buf.String()
+
+	tests := []struct {
+		name  string
+		text  string
+		count int
+	}{
+		{name: "Cipher suite na seção TLS", text: "TLS 1.3 TLS_AES_128_GCM_SHA256: 10", count: 1},
+		{name: "Codificação na seção de compressão", text: "gzip: 10", count: 1},
+		{name: "Atributo TLS fora da lista genérica", text: "▶ " + domain.AttributeTLS + "\n", count: 0},
+		{name: "Atributo de codificação fora da lista genérica", text: "▶ " + domain.AttributeContentEncoding, count: 0},
+		{name: "Demais atributos na lista genérica", text: "▶ grpc.status", count: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Count(out, tt.text); got != tt.count {
+				t.Errorf("Ocorrências de %q incorretas: got %d, want %d\n%s", tt.text, got, tt.count, out)
+			}
+		})
+	}
+}
+
+func TestEmptyReport(t *testing.T) {
+	// teste cancelado antes da primeira resposta
+	report := &domain.TestReport{TotalDuration: time.Second, StatusDistrib: map[int]int{}}
+
+	var buf bytes.Buffer
+	cli.NewReportPresenter(&buf, cli.OutputOptions{}).Present(report)
+	out := buf.String()
+
+	if strings.Contains(out, "NaN") {
+		t.Errorf("Relatório sem requests não deveria ter porcentagens NaN:\n%s", out)
+	}
+	if !strings.Contains(out, "Requests OK (2xx): 0 (0.0%)") {
+		t.Errorf("Taxa de sucesso incorreta para relatório vazio:\n%s", out)
+	}
+}
@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/pkg/stress"
+	"sync"
+	"testing"
+	"time"
+)
+
+// executor que registra o inicio de cada requisicao por worker
+type startRecorder struct {
+	mu     sync.Mutex
+	starts map[int][]time.Time
+}
+
+func (r *startRecorder) Execute(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+	r.mu.Lock()
+	if r.starts == nil {
+		r.starts = make(map[int][]time.Time)
+	}
+	r.starts[req.WorkerID] = append(r.starts[req.WorkerID], time.Now())
+	r.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+	return &domain.TestResult{Duration: time.Millisecond, Status: 200}, nil
+}
+
+// menor intervalo entre inicios consecutivos do mesmo worker
+func (r *startRecorder) minGap() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	gap := time.Duration(-1)
+	for _, starts := range r.starts {
+		for i := 1; i < len(starts); i++ {
+			if d := starts[i].Sub(starts[i-1]); gap < 0 || d < gap {
+				gap = d
+			}
+		}
+	}
+	return gap
+}
+
+func TestThinkTimeAndPacing(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []stress.Option
+		minGap  time.Duration
+	}{
+		{
+			name:    "think time constante",
+			options: []stress.Option{stress.WithThinkTime(stress.ThinkTime{Distribution: domain.ThinkConstant, Mean: 30 * time.Millisecond})},
+			minGap:  30 * time.Millisecond,
+		},
+		{
+			name:    "think time uniforme",
+			options: []stress.Option{stress.WithThinkTime(stress.ThinkTime{Distribution: domain.ThinkUniform, Min: 20 * time.Millisecond, Max: 40 * time.Millisecond})},
+			minGap:  20 * time.Millisecond,
+		},
+		{
+			name:    "pacing",
+			options: []stress.Option{stress.WithPacing(40 * time.Millisecond)},
+			minGap:  40 * time.Millisecond,
+		},
+		{
+			name: "pacing maior que o think time",
+			options: []stress.Option{
+				stress.WithPacing(40 * time.Millisecond),
+				stress.WithThinkTime(stress.ThinkTime{Distribution: domain.ThinkConstant, Mean: 5 * time.Millisecond}),
+			},
+			minGap: 40 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &startRecorder{}
+			options := append([]stress.Option{
+				stress.WithRequests(6),
+				stress.WithConcurrency(2),
+				stress.WithExecutor(recorder),
+			}, tt.options...)
+
+			report, err := stress.Run(context.Background(), "custom://alvo", options...)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.TotalRequests != 6 {
+				t.Errorf("Total de requests incorreto: got %d, want 6", report.TotalRequests)
+			}
+			if gap := recorder.minGap(); gap < tt.minGap {
+				t.Errorf("Intervalo entre requests do mesmo worker: got %v, want >= %v", gap, tt.minGap)
+			}
+			// a pausa entre iteracoes nao entra na latencia das requests
+			if report.AverageDuration >= tt.minGap {
+				t.Errorf("Latência não deveria incluir a pausa: got %v", report.AverageDuration)
+			}
+		})
+	}
+}
+
+func TestThinkTimeValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		think stress.ThinkTime
+		valid bool
+	}{
+		{"desabilitado", stress.ThinkTime{}, true},
+		{"normal", stress.ThinkTime{Distribution: domain.ThinkNormal, Mean: time.Millisecond, StdDev: time.Millisecond}, true},
+		{"exponencial", stress.ThinkTime{Distribution: domain.ThinkExponential, Mean: time.Millisecond}, true},
+		{"distribuicao desconhecida", stress.ThinkTime{Distribution: "poisson", Mean: time.Second}, false},
+		{"intervalo invertido", stress.ThinkTime{Distribution: domain.ThinkUniform, Min: 2 * time.Second, Max: time.Second}, false},
+		{"media negativa", stress.ThinkTime{Distribution: domain.ThinkConstant, Mean: -time.Second}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := stress.Run(context.Background(), "custom://alvo",
+				stress.WithRequests(2),
+				stress.WithExecutor(&startRecorder{}),
+				stress.WithThinkTime(tt.think),
+			)
+			if tt.valid && err != nil {
+				t.Errorf("Erro inesperado: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, stress.ErrInvalidConfig) {
+				t.Errorf("Esperado ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
+
+func TestThinkTimeSample(t *testing.T) {
+	const samples = 20000
+
+	tests := []struct {
+		name      string
+		think     domain.ThinkTime
+		bounded   bool // as pausas devem ficar entre min e max
+		min       time.Duration
+		max       time.Duration
+		mean      time.Duration
+		zeroShare float64 // fracao esperada de pausas zeradas
+	}{
+		{
+			name:    "Sem distribuição",
+			think:   domain.ThinkTime{},
+			bounded: true,
+		},
+		{
+			name:    "Constante",
+			think:   domain.ThinkTime{Distribution: domain.ThinkConstant, Mean: 30 * time.Millisecond},
+			bounded: true,
+			min:     30 * time.Millisecond,
+			max:     30 * time.Millisecond,
+			mean:    30 * time.Millisecond,
+		},
+		{
+			name:    "Uniforme dentro dos limites",
+			think:   domain.ThinkTime{Distribution: domain.ThinkUniform, Min: 20 * time.Millisecond, Max: 40 * time.Millisecond},
+			bounded: true,
+			min:     20 * time.Millisecond,
+			max:     40 * time.Millisecond,
+			mean:    30 * time.Millisecond,
+		},
+		{
+			name:  "Normal",
+			think: domain.ThinkTime{Distribution: domain.ThinkNormal, Mean: 100 * time.Millisecond, StdDev: 10 * time.Millisecond},
+			mean:  100 * time.Millisecond,
+		},
+		{
+			name:      "Normal com valores negativos zerados",
+			think:     domain.ThinkTime{Distribution: domain.ThinkNormal, StdDev: 10 * time.Millisecond},
+			zeroShare: 0.5,
+			// media da normal de media zero truncada em zero: desvio padrao / raiz de 2*pi
+			mean: 3989 * time.Microsecond,
+		},
+		{
+			name:  "Exponencial",
+			think: domain.ThinkTime{Distribution: domain.ThinkExponential, Mean: 50 * time.Millisecond},
+			mean:  50 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var total time.Duration
+			zeros := 0
+			for i := 0; i < samples; i++ {
+				d := tt.think.Sample()
+				if d < 0 || tt.bounded && (d < tt.min || d > tt.max) {
+					t.Fatalf("Pausa fora dos limites: got %v, want entre %v e %v", d, tt.min, tt.max)
+				}
+				if d == 0 {
+					zeros++
+				}
+				total += d
+			}
+
+			mean := total / samples
+			if diff := mean - tt.mean; diff < -tt.mean/20 || diff > tt.mean/20 {
+				t.Errorf("Média fora da tolerância de 5%%: got %v, want %v", mean, tt.mean)
+			}
+			if tt.zeroShare > 0 {
+				if share := float64(zeros) / samples; share < tt.zeroShare-0.05 || share > tt.zeroShare+0.05 {
+					t.Errorf("Fração de pausas zeradas incorreta: got %.2f, want %.2f", share, tt.zeroShare)
+				}
+			}
+		})
+	}
+}
+
+func TestNextIteration(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(100 * time.Millisecond)
+	think := domain.ThinkTime{Distribution: domain.ThinkConstant, Mean: 50 * time.Millisecond}
+
+	tests := []struct {
+		name   string
+		config domain.TestConfig
+		want   time.Time
+	}{
+		{name: "Sem pausa", config: domain.TestConfig{}, want: end},
+		{name: "Think time conta a partir do fim", config: domain.TestConfig{ThinkTime: think}, want: end.Add(50 * time.Millisecond)},
+		{name: "Pacing conta a partir do início", config: domain.TestConfig{Pacing: time.Second}, want: start.Add(time.Second)},
+		{name: "Pacing menor que a iteração", config: domain.TestConfig{Pacing: 50 * time.Millisecond}, want: end},
+		{name: "Vale o que terminar por último", config: domain.TestConfig{ThinkTime: think, Pacing: 120 * time.Millisecond}, want: end.Add(50 * time.Millisecond)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.NextIteration(start, end); !got.Equal(tt.want) {
+				t.Errorf("Próxima iteração incorreta: got %v, want %v", got.Sub(start), tt.want.Sub(start))
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/pkg/stress"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// proxy minimo que exige autenticacao basic user:secret; usa CONNECT para alvos https e
+// encaminha as requisicoes de alvos http, adicionando Via as respostas do alvo
+func newConnectProxy() *httptest.Server {
+	expectedAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") != expectedAuth {
+			w.Header().Set("Proxy-Authenticate", `Basic realm="proxy"`)
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		if r.Method != http.MethodConnect {
+			forward(w, r)
+			return
+		}
+
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			target.Close()
+			return
+		}
+
+		go func() {
+			defer target.Close()
+			io.Copy(target, conn)
+		}()
+		go func() {
+			defer conn.Close()
+			io.Copy(conn, target)
+		}()
+	}))
+}
+
+// encaminha a requisicao ao alvo; quando o alvo nao responde, o proprio proxy gera um 502
+func forward(w http.ResponseWriter, r *http.Request) {
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	out.Header.Del("Proxy-Authorization")
+
+	resp, err := http.DefaultTransport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Add("Via", "1.1 test-proxy")
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+func TestProxy(t *testing.T) {
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+
+	proxy := newConnectProxy()
+	defer proxy.Close()
+
+	// porta sem nenhum servidor escutando
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name        string
+		proxy       string
+		proxyUser   string
+		success     int
+		proxyErrors int
+	}{
+		{name: "Deve conectar pelo proxy com credenciais", proxy: proxy.URL, proxyUser: "user:secret", success: 10},
+		{name: "Deve classificar autenticação recusada como erro de proxy", proxy: proxy.URL, proxyUser: "user:wrong", proxyErrors: 10},
+		{name: "Deve classificar proxy inacessível como erro de proxy", proxy: "http://" + closedAddr, proxyErrors: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.TLS.Insecure = true
+			opts.Proxy = tt.proxy
+			opts.ProxyUser = tt.proxyUser
+
+			report, err := stress.Run(context.Background(), target.URL,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.SuccessRequests != tt.success {
+				t.Errorf("Requests com sucesso incorretas: got %d, want %d", report.SuccessRequests, tt.success)
+			}
+			if report.ErrorsByClass["proxy"] != tt.proxyErrors {
+				t.Errorf("Erros de proxy incorretos: got %v, want %d", report.ErrorsByClass, tt.proxyErrors)
+			}
+		})
+	}
+}
+
+func TestForwardProxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bad-gateway" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+
+	proxy := newConnectProxy()
+	defer proxy.Close()
+
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name        string
+		target      string
+		proxyUser   string
+		success     int
+		proxyErrors int
+		status502   int
+	}{
+		{name: "Deve encaminhar requests http pelo proxy", target: target.URL, proxyUser: "user:secret", success: 10},
+		{name: "Deve classificar 407 do proxy como erro de proxy", target: target.URL, proxyUser: "user:wrong", proxyErrors: 10},
+		{name: "Deve classificar 502 gerado pelo proxy como erro de proxy", target: "http://" + closedAddr, proxyUser: "user:secret", proxyErrors: 10},
+		{name: "Deve manter o 502 encaminhado do alvo", target: target.URL + "/bad-gateway", proxyUser: "user:secret", status502: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.Proxy = proxy.URL
+			opts.ProxyUser = tt.proxyUser
+
+			report, err := stress.Run(context.Background(), tt.target,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.SuccessRequests != tt.success {
+				t.Errorf("Requests com sucesso incorretas: got %d, want %d", report.SuccessRequests, tt.success)
+			}
+			if report.ErrorsByClass["proxy"] != tt.proxyErrors {
+				t.Errorf("Erros de proxy incorretos: got %v, want %d", report.ErrorsByClass, tt.proxyErrors)
+			}
+			if report.StatusDistrib[http.StatusBadGateway] != tt.status502 {
+				t.Errorf("Respostas 502 do alvo incorretas: got %v, want %d", report.StatusDistrib, tt.status502)
+			}
+		})
+	}
+}
+
+// inicia um proxy SOCKS5 minimo que exige usuario e senha (RFC 1928 e RFC 1929)
+func newSOCKS5Proxy(t *testing.T, username, password string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSOCKS5(conn, username, password)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func serveSOCKS5(conn net.Conn, username, password string) {
+	defer conn.Close()
+
+	// saudacao: versao e metodos de autenticacao aceitos pelo cliente
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return
+	}
+	conn.Write([]byte{5, 2})
+
+	// subnegociacao de usuario e senha
+	readField := func() string {
+		size := make([]byte, 1)
+		io.ReadFull(conn, size)
+		field := make([]byte, size[0])
+		io.ReadFull(conn, field)
+		return string(field)
+	}
+	if _, err := io.ReadFull(conn, make([]byte, 1)); err != nil {
+		return
+	}
+	if readField() != username || readField() != password {
+		conn.Write([]byte{1, 1})
+		return
+	}
+	conn.Write([]byte{1, 0})
+
+	// pedido CONNECT com endereco IPv4 ou nome de host
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		ip := make([]byte, 4)
+		io.ReadFull(conn, ip)
+		host = net.IP(ip).String()
+	case 3:
+		host = readField()
+	default:
+		return
+	}
+	port := make([]byte, 2)
+	io.ReadFull(conn, port)
+
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+	if err != nil {
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+	conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+
+	go io.Copy(target, conn)
+	io.Copy(conn, target)
+}
+
+func TestSOCKS5Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+
+	proxyAddr := newSOCKS5Proxy(t, "user", "secret")
+
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name        string
+		target      string
+		proxyUser   string
+		success     int
+		proxyErrors int
+	}{
+		{name: "Deve conectar pelo proxy SOCKS5 com credenciais", target: target.URL, proxyUser: "user:secret", success: 10},
+		{name: "Deve classificar credenciais recusadas como erro de proxy", target: target.URL, proxyUser: "user:wrong", proxyErrors: 10},
+		{name: "Deve classificar proxy sem autenticação compatível como erro de proxy", target: target.URL, proxyErrors: 10},
+		{name: "Deve classificar alvo inacessível pelo proxy como erro de proxy", target: "http://" + closedAddr, proxyUser: "user:secret", proxyErrors: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.Proxy = "socks5://" + proxyAddr
+			opts.ProxyUser = tt.proxyUser
+
+			report, err := stress.Run(context.Background(), tt.target,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.SuccessRequests != tt.success {
+				t.Errorf("Requests com sucesso incorretas: got %d, want %d", report.SuccessRequests, tt.success)
+			}
+			if report.ErrorsByClass["proxy"] != tt.proxyErrors {
+				t.Errorf("Erros de proxy incorretos: got %v, want %d", report.ErrorsByClass, tt.proxyErrors)
+			}
+		})
+	}
+}
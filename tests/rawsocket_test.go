@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"go-expert-stress-test/infra/rawsocket"
+	"go-expert-stress-test/pkg/stress"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// servidor TCP que responde cada linha recebida em dois pedacos, para exercitar a leitura
+// ate o delimitador
+func newTCPEchoServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Erro ao abrir listener: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					conn.Write([]byte("echo:"))
+					time.Sleep(time.Millisecond)
+					conn.Write([]byte(line))
+				}
+			}(conn)
+		}
+	}()
+	return "tcp://" + listener.Addr().String()
+}
+
+func newUDPEchoServer(t *testing.T) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Erro ao abrir socket UDP: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo(buf[:n], addr)
+		}
+	}()
+	return "udp://" + conn.LocalAddr().String()
+}
+
+func TestRawSocketEcho(t *testing.T) {
+	tcpTarget := newTCPEchoServer(t)
+	udpTarget := newUDPEchoServer(t)
+
+	tests := []struct {
+		name        string
+		target      string
+		opts        rawsocket.Options
+		connsOpened int
+		bytes       int64
+	}{
+		{
+			name:        "TCP com delimitador",
+			target:      tcpTarget,
+			opts:        rawsocket.Options{Payload: []byte("ping\n"), Delimiter: []byte("\n")},
+			connsOpened: 4,
+			bytes:       20 * 10,
+		},
+		{
+			name:        "TCP com número de bytes",
+			target:      tcpTarget,
+			opts:        rawsocket.Options{Payload: []byte("ping\n"), ResponseBytes: 10},
+			connsOpened: 4,
+			bytes:       20 * 10,
+		},
+		{
+			name:        "TCP com uma conexão por request",
+			target:      tcpTarget,
+			opts:        rawsocket.Options{Payload: []byte("ping\n"), Delimiter: []byte("\n"), DisableKeepAlives: true},
+			connsOpened: 20,
+			bytes:       20 * 10,
+		},
+		{
+			name:        "UDP não registra conexões",
+			target:      udpTarget,
+			opts:        rawsocket.Options{Payload: []byte("ping")},
+			connsOpened: 0,
+			bytes:       20 * 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Timeout = 2 * time.Second
+			client, err := rawsocket.NewClient(tt.opts)
+			if err != nil {
+				t.Fatalf("Erro ao criar cliente: %v", err)
+			}
+
+			report, err := stress.Run(context.Background(), tt.target,
+				stress.WithRequests(20),
+				stress.WithConcurrency(4),
+				stress.WithExecutor(client),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.StatusDistrib[200] != 20 {
+				t.Errorf("Respostas completas incorretas: got %v (erros %v), want 20", report.StatusDistrib, report.ErrorsByClass)
+			}
+			if report.ConnsOpened != tt.connsOpened {
+				t.Errorf("Conexões abertas incorretas: got %d, want %d", report.ConnsOpened, tt.connsOpened)
+			}
+			if report.WireBytes != tt.bytes {
+				t.Errorf("Bytes recebidos incorretos: got %d, want %d", report.WireBytes, tt.bytes)
+			}
+			if (report.AvgConnectTime > 0) != (tt.connsOpened > 0) {
+				t.Errorf("Tempo de conexão incorreto para %d conexões: %v", tt.connsOpened, report.AvgConnectTime)
+			}
+		})
+	}
+}
+
+func TestRawSocketSurplusBytes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Erro ao abrir listener: %v", err)
+	}
+	defer listener.Close()
+
+	// cada linha recebe duas respostas na mesma escrita; a segunda deve ser lida pela proxima requisicao
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					if _, err := reader.ReadString('\n'); err != nil {
+						return
+					}
+					conn.Write([]byte("a\nb\n"))
+				}
+			}(conn)
+		}
+	}()
+
+	report, err := stress.Run(context.Background(), "tcp://"+listener.Addr().String(),
+		stress.WithRequests(20),
+		stress.WithConcurrency(4),
+		stress.WithRawOptions(rawsocket.Options{
+			Payload:   []byte("ping\n"),
+			Delimiter: []byte("\n"),
+			Timeout:   2 * time.Second,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if report.StatusDistrib[200] != 20 {
+		t.Errorf("Respostas completas incorretas: got %v (erros %v), want 20", report.StatusDistrib, report.ErrorsByClass)
+	}
+	if report.WireBytes != 20*2 {
+		t.Errorf("Cada resposta deveria terminar no delimitador: got %d bytes, want %d", report.WireBytes, 20*2)
+	}
+}
+
+func TestRawSocketTimeout(t *testing.T) {
+	target := newTCPEchoServer(t)
+
+	// o delimitador nunca chega, a resposta expira
+	report, err := stress.Run(context.Background(), target,
+		stress.WithRequests(3),
+		stress.WithRawOptions(rawsocket.Options{
+			Payload:   []byte("ping\n"),
+			Delimiter: []byte("END"),
+			Timeout:   50 * time.Millisecond,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if report.ErrorsByClass["timeout"] != 3 {
+		t.Errorf("Timeouts incorretos: got %v, want 3", report.ErrorsByClass)
+	}
+}
+
+func TestRawSocketCancel(t *testing.T) {
+	// servidor que le o payload e nunca responde
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Erro ao abrir listener: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				io.Copy(io.Discard, conn)
+			}(conn)
+		}
+	}()
+
+	// sem timeout, apenas o cancelamento do contexto encerra a espera pela resposta
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = stress.Run(ctx, "tcp://"+listener.Addr().String(),
+		stress.WithRequests(4),
+		stress.WithConcurrency(2),
+		stress.WithRawOptions(rawsocket.Options{Payload: []byte("ping\n"), Delimiter: []byte("\n")}),
+	)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Erro incorreto: got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("O cancelamento deveria interromper a leitura: teste levou %v", elapsed)
+	}
+}
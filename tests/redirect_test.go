@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/pkg/stress"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectPolicy(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/final", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/hop2", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/final", http.StatusFound)
+	})
+	mux.HandleFunc("/hop1", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/hop2", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusMovedPermanently)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	redirects := func(n int) *int { return &n }
+
+	tests := []struct {
+		name         string
+		path         string
+		maxRedirects *int
+		status       int
+		redirected   int
+		hops         int
+		chain        string
+	}{
+		{name: "Segue a cadeia completa", path: "/hop1", status: 200, redirected: 10, hops: 20, chain: "301 -> 200"},
+		{name: "Zero não segue redirects", path: "/hop1", maxRedirects: redirects(0), status: 301},
+		{name: "Limite atingido retorna o último redirect", path: "/hop1", maxRedirects: redirects(1), status: 302, redirected: 10, hops: 10, chain: "301 -> 302"},
+		{name: "Loop aparece como 301", path: "/loop", maxRedirects: redirects(3), status: 301, redirected: 10, hops: 30, chain: "301 -> 301"},
+		{name: "Loop para no limite padrão", path: "/loop", status: 301, redirected: 10, hops: 100, chain: "301 -> 301"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.MaxRedirects = tt.maxRedirects
+
+			report, err := stress.Run(context.Background(), server.URL+tt.path,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.StatusDistrib[tt.status] != 10 {
+				t.Errorf("Distribuição de status incorreta: got %v, want 10x %d", report.StatusDistrib, tt.status)
+			}
+			if report.Redirected != tt.redirected {
+				t.Errorf("Requests redirecionadas incorretas: got %d, want %d", report.Redirected, tt.redirected)
+			}
+			if report.RedirectHops != tt.hops {
+				t.Errorf("Total de redirects incorreto: got %d, want %d", report.RedirectHops, tt.hops)
+			}
+			if tt.chain != "" && report.RedirectChains[tt.chain] != tt.redirected {
+				t.Errorf("Cadeias de redirect incorretas: got %v, want %s", report.RedirectChains, tt.chain)
+			}
+		})
+	}
+}
+
+func TestInvalidRedirectLimit(t *testing.T) {
+	negative := -1
+	opts := httpclient.DefaultOptions(1)
+	opts.MaxRedirects = &negative
+
+	if _, err := httpclient.NewClient(opts); err == nil {
+		t.Error("Limite de redirects negativo deveria retornar erro")
+	}
+}
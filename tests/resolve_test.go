@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"context"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/pkg/stress"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveOverride(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	opts := httpclient.DefaultOptions(2)
+	opts.Resolve = []string{"backend.invalid:" + port + ":127.0.0.1"}
+
+	report, err := stress.Run(context.Background(), "http://backend.invalid:"+port,
+		stress.WithRequests(10),
+		stress.WithConcurrency(2),
+		stress.WithClientOptions(opts),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if report.SuccessRequests != 10 {
+		t.Errorf("Requests com sucesso incorretas: got %d, want 10 (erros: %v)", report.SuccessRequests, report.ErrorsByClass)
+	}
+	if stats := report.IPStats["127.0.0.1"]; stats.Requests != 10 || stats.Errors != 0 {
+		t.Errorf("Métricas por IP incorretas: got %+v", report.IPStats)
+	}
+}
+
+func TestInvalidResolveEntry(t *testing.T) {
+	for _, entry := range []string{"backend.invalid:8080", "backend.invalid:8080:not-an-ip", ":8080:127.0.0.1"} {
+		opts := httpclient.DefaultOptions(1)
+		opts.Resolve = []string{entry}
+
+		if _, err := httpclient.NewClient(opts); err == nil {
+			t.Errorf("Entrada inválida %q deveria retornar erro", entry)
+		}
+	}
+}
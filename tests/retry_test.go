@@ -0,0 +1,235 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/observer"
+	"go-expert-stress-test/pkg/stress"
+	"go-expert-stress-test/usecases"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAccounting(t *testing.T) {
+	// alterna entre falha e sucesso a cada chamada
+	alternatingWith := func(failure domain.TestResult, success int) domain.Executor {
+		var calls atomic.Int64
+		return domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+			if calls.Add(1)%2 == 1 {
+				result := failure
+				return &result, nil
+			}
+			return &domain.TestResult{Duration: time.Millisecond, Status: success}, nil
+		})
+	}
+	alternating := func(failure domain.TestResult) domain.Executor {
+		return alternatingWith(failure, 200)
+	}
+	always := func(failure domain.TestResult) domain.Executor {
+		return domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+			result := failure
+			return &result, nil
+		})
+	}
+
+	tests := []struct {
+		name          string
+		executor      domain.Executor
+		policy        domain.RetryPolicy
+		wantSuccess   int
+		wantFirstOK   int
+		wantRetried   int
+		wantRetries   int
+		wantRecovered int
+	}{
+		{
+			name:        "sem retries",
+			executor:    alternating(domain.TestResult{Status: 503}),
+			wantSuccess: 5,
+			wantFirstOK: 5,
+		},
+		{
+			name:          "status padrao e repetido",
+			executor:      alternating(domain.TestResult{Status: 503}),
+			policy:        domain.RetryPolicy{MaxRetries: 2},
+			wantSuccess:   10,
+			wantRetried:   10,
+			wantRetries:   10,
+			wantRecovered: 10,
+		},
+		{
+			name:          "recuperada com outro status 2xx",
+			executor:      alternatingWith(domain.TestResult{Status: 503}, 204),
+			policy:        domain.RetryPolicy{MaxRetries: 2},
+			wantSuccess:   10,
+			wantRetried:   10,
+			wantRetries:   10,
+			wantRecovered: 10,
+		},
+		{
+			name:        "sucesso 2xx na primeira tentativa",
+			executor:    alternatingWith(domain.TestResult{Status: 404}, 201),
+			policy:      domain.RetryPolicy{MaxRetries: 2},
+			wantSuccess: 5,
+			wantFirstOK: 5,
+		},
+		{
+			name:        "status fora da lista nao e repetido",
+			executor:    alternating(domain.TestResult{Status: 500}),
+			policy:      domain.RetryPolicy{MaxRetries: 2},
+			wantSuccess: 5,
+			wantFirstOK: 5,
+		},
+		{
+			name:          "status configurado",
+			executor:      alternating(domain.TestResult{Status: 429}),
+			policy:        domain.RetryPolicy{MaxRetries: 1, Statuses: []int{429}},
+			wantSuccess:   10,
+			wantRetried:   10,
+			wantRetries:   10,
+			wantRecovered: 10,
+		},
+		{
+			name:          "classe de erro",
+			executor:      alternating(domain.TestResult{Error: fmt.Errorf("%w: deadline", domain.ErrTimeout)}),
+			policy:        domain.RetryPolicy{MaxRetries: 1, ErrorClasses: []string{"timeout"}},
+			wantSuccess:   10,
+			wantRetried:   10,
+			wantRetries:   10,
+			wantRecovered: 10,
+		},
+		{
+			name:        "classe de erro fora da lista",
+			executor:    alternating(domain.TestResult{Error: fmt.Errorf("%w: refused", domain.ErrConnection)}),
+			policy:      domain.RetryPolicy{MaxRetries: 1, ErrorClasses: []string{"timeout"}},
+			wantSuccess: 5,
+			wantFirstOK: 5,
+		},
+		{
+			name:        "retries esgotados",
+			executor:    always(domain.TestResult{Status: 503}),
+			policy:      domain.RetryPolicy{MaxRetries: 3},
+			wantRetried: 10,
+			wantRetries: 30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTester := usecases.NewLoadTesterUseCase(tt.executor, usecases.NewReporter(), observer.NewNoop())
+			report, err := loadTester.Execute(domain.TestConfig{URL: "custom://alvo", Requests: 10, Concurrency: 1, Retry: tt.policy})
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.TotalRequests != 10 {
+				t.Errorf("Retries não deveriam contar como requests: got %d, want 10", report.TotalRequests)
+			}
+			if report.SuccessRequests != tt.wantSuccess || report.FirstAttemptOK != tt.wantFirstOK {
+				t.Errorf("Sucessos incorretos: got %d (%d na primeira tentativa), want %d (%d)",
+					report.SuccessRequests, report.FirstAttemptOK, tt.wantSuccess, tt.wantFirstOK)
+			}
+			if report.Retried != tt.wantRetried || report.Retries != tt.wantRetries || report.RetriesRecovered != tt.wantRecovered {
+				t.Errorf("Retries incorretos: got %d requests, %d retries e %d recuperadas, want %d, %d e %d",
+					report.Retried, report.Retries, report.RetriesRecovered, tt.wantRetried, tt.wantRetries, tt.wantRecovered)
+			}
+		})
+	}
+}
+
+func TestRetryBackoffLatency(t *testing.T) {
+	// as duas primeiras requisicoes recebidas pelo servidor falham
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	report, err := stress.Run(context.Background(), server.URL,
+		stress.WithRequests(3),
+		stress.WithRetry(stress.RetryPolicy{MaxRetries: 3, BaseDelay: 20 * time.Millisecond, MaxDelay: 30 * time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if report.SuccessRequests != 3 || report.FirstAttemptOK != 2 {
+		t.Errorf("Sucessos incorretos: got %d (%d na primeira tentativa), want 3 (2)", report.SuccessRequests, report.FirstAttemptOK)
+	}
+	if report.Retried != 1 || report.Retries != 2 {
+		t.Errorf("Retries incorretos: got %d requests e %d retries, want 1 e 2", report.Retried, report.Retries)
+	}
+	// esperas de ao menos 10ms e 15ms, metade de cada intervalo e sorteada
+	if report.AvgRetryLatency < 25*time.Millisecond {
+		t.Errorf("Latência extra deveria incluir o backoff: got %v, want >= 25ms", report.AvgRetryLatency)
+	}
+	if report.AverageDuration < report.AvgRetryLatency/3 {
+		t.Errorf("Duração média deveria incluir a latência dos retries: got %v", report.AverageDuration)
+	}
+}
+
+func TestRetryRateLimit(t *testing.T) {
+	// todas as tentativas falham, entao cada requisicao faz 1 + 3 tentativas
+	var calls atomic.Int64
+	executor := domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+		calls.Add(1)
+		return &domain.TestResult{Duration: time.Millisecond, Status: 503}, nil
+	})
+
+	config := domain.TestConfig{
+		URL:         "http://test.com",
+		Requests:    5,
+		Concurrency: 5,
+		Rate:        50,
+		Retry:       domain.RetryPolicy{MaxRetries: 3, BaseDelay: time.Nanosecond},
+	}
+	loadTester := usecases.NewLoadTesterUseCase(executor, usecases.NewReporter(), observer.NewNoop())
+
+	start := time.Now()
+	if _, err := loadTester.Execute(config); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	// 20 tentativas a 50/s precisam de ao menos 20 ticks de 20ms
+	if calls.Load() != 20 {
+		t.Errorf("Tentativas incorretas: got %d, want 20", calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed < 380*time.Millisecond {
+		t.Errorf("Retries deveriam respeitar o limite de taxa: 20 tentativas em %v", elapsed)
+	}
+}
+
+func TestErrorClassesCoverClassification(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "Proxy", err: domain.ErrProxy},
+		{name: "Autenticação", err: domain.ErrAuth},
+		{name: "DNS", err: domain.ErrDNS},
+		{name: "Timeout", err: fmt.Errorf("%w: lento", domain.ErrTimeout)},
+		{name: "Conexão", err: domain.ErrConnection},
+		{name: "TLS", err: domain.ErrTLS},
+		{name: "Decodificação", err: domain.ErrDecode},
+		{name: "GraphQL", err: domain.ErrGraphQL},
+		{name: "Outros", err: fmt.Errorf("inesperado")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class := domain.ErrorClass(tt.err)
+			if !slices.Contains(domain.ErrorClasses, class) {
+				t.Errorf("Classe %q não aceita pelo --retry-on: %v", class, domain.ErrorClasses)
+			}
+		})
+	}
+}
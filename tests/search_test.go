@@ -0,0 +1,222 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/pkg/stress"
+	"go-expert-stress-test/tests/mocks"
+	"go-expert-stress-test/usecases"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFindMax(t *testing.T) {
+	// a latencia cresce com o numero do worker, entao o p99 acompanha a concorrencia; a barreira
+	// de cada degrau garante que todos os workers retirem requisicoes da fila
+	slowWorkers := domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+		return &domain.TestResult{Duration: time.Duration(req.WorkerID+1) * time.Millisecond, Status: 200}, nil
+	})
+	// a partir do sexto worker todas as requisicoes falham
+	failingWorkers := domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+		if req.WorkerID >= 5 {
+			return &domain.TestResult{Duration: time.Millisecond, Status: 503}, nil
+		}
+		return &domain.TestResult{Duration: time.Millisecond, Status: 200}, nil
+	})
+
+	// respostas 2xx diferentes de 200 tambem contam como sucesso
+	createdWorkers := domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+		return &domain.TestResult{Duration: time.Millisecond, Status: 201}, nil
+	})
+
+	tests := []struct {
+		name            string
+		executor        domain.Executor
+		search          stress.SearchConfig
+		wantLevels      []int
+		wantSustainable int
+		wantBreach      string
+	}{
+		{
+			name:            "p99 acima do limite",
+			executor:        slowWorkers,
+			search:          stress.SearchConfig{Mode: domain.SearchConcurrency, Start: 2, Step: 2, Max: 20, MaxP99: 5 * time.Millisecond, MaxErrorRate: 1},
+			wantLevels:      []int{2, 4, 6},
+			wantSustainable: 4,
+			wantBreach:      domain.BreachP99,
+		},
+		{
+			name:            "taxa de erros acima do limite",
+			executor:        failingWorkers,
+			search:          stress.SearchConfig{Mode: domain.SearchConcurrency, Start: 1, Step: 2, Max: 20, MaxErrorRate: 1},
+			wantLevels:      []int{1, 3, 5, 7},
+			wantSustainable: 5,
+			wantBreach:      domain.BreachErrorRate,
+		},
+		{
+			name:       "primeiro degrau ja ultrapassa",
+			executor:   slowWorkers,
+			search:     stress.SearchConfig{Mode: domain.SearchConcurrency, Start: 10, Step: 10, Max: 50, MaxP99: 5 * time.Millisecond},
+			wantLevels: []int{10},
+			wantBreach: domain.BreachP99,
+		},
+		{
+			name:            "respostas 201 nao contam como erro",
+			executor:        createdWorkers,
+			search:          stress.SearchConfig{Mode: domain.SearchConcurrency, Start: 1, Step: 1, Max: 3, MaxErrorRate: 1},
+			wantLevels:      []int{1, 2, 3},
+			wantSustainable: 3,
+		},
+		{
+			name:            "nivel maximo sem ultrapassar",
+			executor:        slowWorkers,
+			search:          stress.SearchConfig{Mode: domain.SearchRate, Start: 100, Step: 100, Max: 300},
+			wantLevels:      []int{100, 200, 300},
+			wantSustainable: 300,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barrier := mocks.NewBarrierExecutor(tt.executor)
+			report, err := stress.FindMax(context.Background(), "custom://alvo", tt.search,
+				stress.WithRequests(40),
+				stress.WithConcurrency(4),
+				stress.WithExecutor(barrier),
+				stress.WithEventHandler(barrier.OnEvent),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			levels := make([]int, 0, len(report.Steps))
+			for _, step := range report.Steps {
+				levels = append(levels, step.Level)
+				if step.Report == nil || step.Report.TotalRequests != 40 {
+					t.Errorf("Degrau %d deveria executar 40 requests", step.Level)
+				}
+			}
+			if !slices.Equal(levels, tt.wantLevels) {
+				t.Errorf("Degraus incorretos: got %v, want %v", levels, tt.wantLevels)
+			}
+
+			sustainable := 0
+			if report.Sustainable != nil {
+				sustainable = report.Sustainable.Level
+			}
+			if sustainable != tt.wantSustainable {
+				t.Errorf("Nível sustentável incorreto: got %d, want %d", sustainable, tt.wantSustainable)
+			}
+			if breach := report.Steps[len(report.Steps)-1].Breach; breach != tt.wantBreach {
+				t.Errorf("Limite ultrapassado incorreto: got %q, want %q", breach, tt.wantBreach)
+			}
+		})
+	}
+}
+
+func TestFindMaxAppliesLevel(t *testing.T) {
+	tests := []struct {
+		name            string
+		search          stress.SearchConfig
+		concurrency     int
+		wantRates       []int
+		wantConcurrency []int
+	}{
+		{
+			name:            "Modo rate acompanha a taxa com os workers",
+			search:          stress.SearchConfig{Mode: domain.SearchRate, Start: 500, Step: 500, Max: 1000, MaxErrorRate: 1},
+			concurrency:     3,
+			wantRates:       []int{500, 1000},
+			wantConcurrency: []int{500, 1000},
+		},
+		{
+			name:            "Modo rate mantém concorrência maior que a taxa",
+			search:          stress.SearchConfig{Mode: domain.SearchRate, Start: 500, Step: 500, Max: 1000, MaxErrorRate: 1},
+			concurrency:     2000,
+			wantRates:       []int{500, 1000},
+			wantConcurrency: []int{2000, 2000},
+		},
+		{
+			name:            "Modo concurrency não limita a taxa",
+			search:          stress.SearchConfig{Mode: domain.SearchConcurrency, Start: 2, Step: 2, Max: 4, MaxErrorRate: 1},
+			concurrency:     3,
+			wantRates:       []int{0, 0},
+			wantConcurrency: []int{2, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var configs []domain.TestConfig
+			var steps int
+
+			_, err := stress.FindMax(context.Background(), "custom://alvo", tt.search,
+				stress.WithRequests(5),
+				stress.WithConcurrency(tt.concurrency),
+				stress.WithExecutor(domain.ExecutorFunc(func(ctx context.Context, req domain.Request) (*domain.TestResult, error) {
+					return &domain.TestResult{Duration: time.Millisecond, Status: 200}, nil
+				})),
+				stress.WithEventHandler(func(event stress.Event) {
+					mu.Lock()
+					defer mu.Unlock()
+					switch event.Type {
+					case domain.EventStarted:
+						configs = append(configs, *event.Config)
+					case domain.EventStepDone:
+						steps++
+					}
+				}),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if len(configs) != 2 || steps != 2 {
+				t.Fatalf("Esperados 2 degraus, got %d testes e %d eventos step_done", len(configs), steps)
+			}
+			for i, config := range configs {
+				if config.Rate != tt.wantRates[i] || config.Concurrency != tt.wantConcurrency[i] {
+					t.Errorf("Degrau %d: got rate %d e concorrência %d, want %d e %d",
+						i, config.Rate, config.Concurrency, tt.wantRates[i], tt.wantConcurrency[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFindMaxValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		search stress.SearchConfig
+	}{
+		{"modo desconhecido", stress.SearchConfig{Mode: "latency", Start: 1, Step: 1, Max: 10}},
+		{"degrau zero", stress.SearchConfig{Mode: domain.SearchRate, Start: 1, Step: 0, Max: 10}},
+		{"maximo menor que o inicio", stress.SearchConfig{Mode: domain.SearchConcurrency, Start: 10, Step: 1, Max: 5}},
+		{"limite negativo", stress.SearchConfig{Mode: domain.SearchConcurrency, Start: 1, Step: 1, Max: 5, MaxErrorRate: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := stress.FindMax(context.Background(), "custom://alvo", tt.search, stress.WithExecutor(&startRecorder{}))
+			if !errors.Is(err, stress.ErrInvalidConfig) {
+				t.Errorf("Esperado ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReportPercentiles(t *testing.T) {
+	results := make([]domain.TestResult, 0, 100)
+	for i := 100; i >= 1; i-- {
+		results = append(results, domain.TestResult{Duration: time.Duration(i) * time.Millisecond, Status: 200})
+	}
+
+	report := usecases.NewReporter().GenerateReport(results, time.Second)
+	if report.P50 != 50*time.Millisecond || report.P95 != 95*time.Millisecond || report.P99 != 99*time.Millisecond {
+		t.Errorf("Percentis incorretos: got p50 %v, p95 %v, p99 %v", report.P50, report.P95, report.P99)
+	}
+}
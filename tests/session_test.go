@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/pkg/stress"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// segura as primeiras n requisicoes ate todas chegarem ao servidor; como cada worker espera a
+// propria resposta, as n primeiras vem de workers diferentes
+func firstRequestsBarrier(n int, next http.HandlerFunc) http.HandlerFunc {
+	var arrived atomic.Int64
+	release := make(chan struct{})
+	return func(w http.ResponseWriter, r *http.Request) {
+		if count := arrived.Add(1); count <= int64(n) {
+			if count == int64(n) {
+				close(release)
+			}
+			select {
+			case <-release:
+			case <-r.Context().Done():
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+func TestCookieJarPerWorker(t *testing.T) {
+	tests := []struct {
+		name          string
+		poolPerWorker bool
+	}{
+		{name: "Pool compartilhado", poolPerWorker: false},
+		{name: "Pool por worker", poolPerWorker: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logins atomic.Int64
+			var mu sync.Mutex
+			requestsBySession := make(map[string]int)
+
+			// a primeira requisicao de cada usuario recebe um cookie de sessao, as seguintes devem
+			// envia-lo; a barreira garante que cada um dos 4 workers faca o seu login
+			server := httptest.NewServer(firstRequestsBarrier(4, func(w http.ResponseWriter, r *http.Request) {
+				cookie, err := r.Cookie("session")
+				if err != nil {
+					id := fmt.Sprintf("user-%d", logins.Add(1))
+					http.SetCookie(w, &http.Cookie{Name: "session", Value: id})
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+				mu.Lock()
+				requestsBySession[cookie.Value]++
+				mu.Unlock()
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			opts := httpclient.DefaultOptions(4)
+			opts.PoolPerWorker = tt.poolPerWorker
+
+			report, err := stress.Run(context.Background(), server.URL,
+				stress.WithRequests(40),
+				stress.WithConcurrency(4),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.SuccessRequests != 40 {
+				t.Errorf("Requests com sucesso incorretas: got %d, want 40", report.SuccessRequests)
+			}
+			if logins.Load() != 4 {
+				t.Errorf("Cada worker deveria receber um único cookie: got %d logins, want 4", logins.Load())
+			}
+			// alem do login, os workers retiram requests de uma fila compartilhada, entao apenas o
+			// total e fixo
+			reused := 0
+			for _, count := range requestsBySession {
+				reused += count
+			}
+			if len(requestsBySession) > 4 || reused != 36 {
+				t.Errorf("Sessões reutilizadas incorretamente: got %v, want 36 requests em até 4 sessões", requestsBySession)
+			}
+			if tt.poolPerWorker && report.ConnsOpened != 4 {
+				t.Errorf("Cada worker deveria abrir uma conexão própria: got %d, want 4", report.ConnsOpened)
+			}
+		})
+	}
+}
+
+func TestPoolPerWorkerClosesIdleConnections(t *testing.T) {
+	var open atomic.Int64
+	// cada worker faz ao menos uma requisicao e abre a conexao do seu pool
+	server := httptest.NewUnstartedServer(firstRequestsBarrier(4, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			open.Add(1)
+		case http.StateClosed, http.StateHijacked:
+			open.Add(-1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	opts := httpclient.DefaultOptions(4)
+	opts.PoolPerWorker = true
+
+	report, err := stress.Run(context.Background(), server.URL,
+		stress.WithRequests(40),
+		stress.WithConcurrency(4),
+		stress.WithClientOptions(opts),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if report.ConnsOpened != 4 {
+		t.Errorf("Cada worker deveria abrir uma conexão própria: got %d, want 4", report.ConnsOpened)
+	}
+
+	// o servidor observa o fechamento de forma assincrona
+	deadline := time.Now().Add(2 * time.Second)
+	for open.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if open.Load() != 0 {
+		t.Errorf("Conexões dos pools dos workers deveriam ser fechadas ao fim do teste: %d abertas", open.Load())
+	}
+}
+
+func TestPoolPerWorkerHTTP2(t *testing.T) {
+	// os transportes dos workers negociam HTTP/2 ao mesmo tempo, cada um sobre a sua copia da
+	// configuracao TLS (verificado com go test -race)
+	server := newTLSServer(t, nil)
+
+	opts := httpclient.DefaultOptions(4)
+	opts.TLS.Insecure = true
+	opts.HTTP2 = true
+	opts.PoolPerWorker = true
+
+	report, err := stress.Run(context.Background(), server.URL,
+		stress.WithRequests(20),
+		stress.WithConcurrency(4),
+		stress.WithClientOptions(opts),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if report.ProtocolDistrib["HTTP/2.0"] != 20 {
+		t.Errorf("Protocolo incorreto: got %v (erros %v), want 20 HTTP/2.0", report.ProtocolDistrib, report.ErrorsByClass)
+	}
+}
@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"go-expert-stress-test/infra/sse"
+	"go-expert-stress-test/pkg/stress"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// servidor que envia um evento a cada interval e encerra o stream apos closeAfter eventos
+// (0 para manter aberto ate o cliente desconectar)
+func newSSEServer(interval time.Duration, closeAfter int) *httptest.Server {
+	return httptest.NewServer(sseHandler(interval, closeAfter))
+}
+
+func sseHandler(interval time.Duration, closeAfter int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ": conectado\n\n")
+		flusher.Flush()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for i := 1; closeAfter == 0 || i <= closeAfter; i++ {
+			select {
+			case <-ticker.C:
+				fmt.Fprintf(w, "event: update\ndata: {\"seq\":%d}\n\n", i)
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+func TestSSEStreams(t *testing.T) {
+	tests := []struct {
+		name        string
+		closeAfter  int
+		opts        sse.Options
+		minEvents   int
+		maxEvents   int
+		disconnects int
+		minHeld     time.Duration
+	}{
+		{
+			name:      "Stream mantido durante o hold",
+			opts:      sse.Options{Hold: 300 * time.Millisecond},
+			minEvents: 4 * 10,
+			maxEvents: 4 * 16,
+			minHeld:   300 * time.Millisecond,
+		},
+		{
+			name:      "Stream encerrado após o limite de eventos",
+			opts:      sse.Options{Hold: 5 * time.Second, MaxEvents: 3},
+			minEvents: 4 * 3,
+			maxEvents: 4 * 3,
+			minHeld:   60 * time.Millisecond,
+		},
+		{
+			name:        "Servidor encerra o stream antes do fim",
+			closeAfter:  2,
+			opts:        sse.Options{Hold: 5 * time.Second},
+			minEvents:   4 * 2,
+			maxEvents:   4 * 2,
+			disconnects: 4,
+			minHeld:     40 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSSEServer(20*time.Millisecond, tt.closeAfter)
+			defer server.Close()
+
+			client, err := sse.NewClient(tt.opts)
+			if err != nil {
+				t.Fatalf("Erro ao criar cliente: %v", err)
+			}
+
+			report, err := stress.Run(context.Background(), server.URL,
+				stress.WithRequests(4),
+				stress.WithConcurrency(4),
+				stress.WithExecutor(client),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.Streams != 4 || report.StatusDistrib[200] != 4 {
+				t.Fatalf("Streams incorretos: got %d (status %v), want 4", report.Streams, report.StatusDistrib)
+			}
+			if report.Events < tt.minEvents || report.Events > tt.maxEvents {
+				t.Errorf("Eventos fora do esperado: got %d, want entre %d e %d", report.Events, tt.minEvents, tt.maxEvents)
+			}
+			if report.Disconnects != tt.disconnects {
+				t.Errorf("Desconexões prematuras incorretas: got %d, want %d", report.Disconnects, tt.disconnects)
+			}
+			if report.AvgFirstEvent < 15*time.Millisecond || report.AvgFirstEvent > time.Second {
+				t.Errorf("Tempo até o primeiro evento fora do esperado: %v", report.AvgFirstEvent)
+			}
+			if report.AvgEventGap < 10*time.Millisecond || report.MaxEventGap < report.AvgEventGap {
+				t.Errorf("Intervalos entre eventos incorretos: média %v, máximo %v", report.AvgEventGap, report.MaxEventGap)
+			}
+			if report.EventsPerSecond <= 0 {
+				t.Errorf("Eventos por segundo deveriam ser registrados: %.1f", report.EventsPerSecond)
+			}
+			if report.AvgStreamHeld < tt.minHeld || report.AvgStreamHeld > 2*time.Second {
+				t.Errorf("Tempo médio aberto fora do esperado: got %v, want >= %v", report.AvgStreamHeld, tt.minHeld)
+			}
+		})
+	}
+}
+
+func TestSSEHTTPOptions(t *testing.T) {
+	// o stream so e aberto com o token bearer configurado
+	authenticated := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		sseHandler(20*time.Millisecond, 0)(w, r)
+	}
+
+	tcpServer := httptest.NewServer(http.HandlerFunc(authenticated))
+	defer tcpServer.Close()
+	_, port, _ := net.SplitHostPort(tcpServer.Listener.Addr().String())
+
+	tests := []struct {
+		name    string
+		target  func(t *testing.T) string
+		resolve []string
+	}{
+		{
+			name:   "Autenticação aplicada ao stream",
+			target: func(t *testing.T) string { return tcpServer.URL },
+		},
+		{
+			name:    "Host resolvido pelo --resolve",
+			target:  func(t *testing.T) string { return "http://sse.exemplo.test:" + port + "/events" },
+			resolve: []string{"sse.exemplo.test:" + port + ":127.0.0.1"},
+		},
+		{
+			name: "Alvo unix",
+			target: func(t *testing.T) string {
+				socketPath := filepath.Join(t.TempDir(), "sse.sock")
+				listener, err := net.Listen("unix", socketPath)
+				if err != nil {
+					t.Skipf("Unix sockets indisponíveis: %v", err)
+				}
+				server := httptest.NewUnstartedServer(http.HandlerFunc(authenticated))
+				server.Listener = listener
+				server.Start()
+				t.Cleanup(server.Close)
+				return "unix://" + socketPath + ":/events"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := sse.Options{Hold: 5 * time.Second, MaxEvents: 2}
+			opts.HTTP.Auth.Bearer = "token-1"
+			opts.HTTP.Resolve = tt.resolve
+
+			client, err := sse.NewClient(opts)
+			if err != nil {
+				t.Fatalf("Erro ao criar cliente: %v", err)
+			}
+
+			report, err := stress.Run(context.Background(), tt.target(t),
+				stress.WithRequests(4),
+				stress.WithConcurrency(2),
+				stress.WithExecutor(client),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+			if report.Streams != 4 || report.Events != 8 {
+				t.Errorf("Streams incorretos: got %d streams e %d eventos (status %v, erros %v), want 4 e 8",
+					report.Streams, report.Events, report.StatusDistrib, report.ErrorsByClass)
+			}
+		})
+	}
+}
+
+func TestSSEReadError(t *testing.T) {
+	// uma linha maior que o limite do cliente nao e uma desconexao do servidor
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "data: %s\n\n", strings.Repeat("x", 2<<20))
+	}))
+	defer server.Close()
+
+	client, err := sse.NewClient(sse.Options{Hold: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Erro ao criar cliente: %v", err)
+	}
+
+	report, err := stress.Run(context.Background(), server.URL,
+		stress.WithRequests(2),
+		stress.WithExecutor(client),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if report.ErrorsByClass["decode"] != 2 {
+		t.Errorf("Erros de leitura incorretos: got %v, want 2 decode", report.ErrorsByClass)
+	}
+	if report.Disconnects != 0 {
+		t.Errorf("Erros de leitura não deveriam contar como desconexões: got %d", report.Disconnects)
+	}
+}
@@ -0,0 +1,323 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"go-expert-stress-test/domain"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/pkg/stress"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// inicia um servidor HTTPS com a configuracao TLS informada, que pode ser nil
+func newTLSServer(t *testing.T, config *tls.Config) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.EnableHTTP2 = true
+	server.TLS = config
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// grava um arquivo PEM no diretorio informado e retorna o caminho
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// gera uma CA e um certificado de cliente assinado por ela; retorna o pool com a CA e os
+// arquivos PEM do certificado e da chave do cliente
+func newClientCert(t *testing.T, name string) (*x509.CertPool, string, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name + " CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caCert, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return pool, writePEM(t, dir, "client.pem", "CERTIFICATE", clientDER), writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+func TestTLSOptions(t *testing.T) {
+	// todos os servidores do httptest usam o mesmo certificado autoassinado, gravado como bundle de CA
+	caFile := writePEM(t, t.TempDir(), "ca.pem", "CERTIFICATE", newTLSServer(t, nil).Certificate().Raw)
+
+	tests := []struct {
+		name       string
+		server     *tls.Config
+		configure  func(opts *httpclient.Options)
+		tlsErrors  int
+		protocol   string
+		tlsVersion string
+		tlsCipher  string
+	}{
+		{
+			name:      "Deve falhar com CA desconhecida",
+			configure: func(opts *httpclient.Options) {},
+			tlsErrors: 10,
+		},
+		{
+			name: "Deve aceitar o servidor com CA customizada",
+			configure: func(opts *httpclient.Options) {
+				opts.TLS.CAFile = caFile
+			},
+			protocol:   "HTTP/1.1",
+			tlsVersion: "TLS 1.3",
+		},
+		{
+			name: "Deve aceitar o servidor em modo inseguro com HTTP/2",
+			configure: func(opts *httpclient.Options) {
+				opts.TLS.Insecure = true
+				opts.HTTP2 = true
+			},
+			protocol:   "HTTP/2.0",
+			tlsVersion: "TLS 1.3",
+		},
+		{
+			name:   "Deve negociar a cipher suite de TLS 1.2 configurada",
+			server: &tls.Config{MaxVersion: tls.VersionTLS12},
+			configure: func(opts *httpclient.Options) {
+				opts.TLS.CAFile = caFile
+				opts.TLS.Ciphers = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+			},
+			protocol:   "HTTP/1.1",
+			tlsVersion: "TLS 1.2",
+			tlsCipher:  "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		},
+		{
+			name:   "Deve falhar sem cipher suite em comum com o servidor",
+			server: &tls.Config{MaxVersion: tls.VersionTLS12, CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}},
+			configure: func(opts *httpclient.Options) {
+				opts.TLS.CAFile = caFile
+				opts.TLS.Ciphers = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+			},
+			tlsErrors: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTLSServer(t, tt.server)
+			opts := httpclient.DefaultOptions(2)
+			// com um pool por worker cada worker abre exatamente uma conexao, e um handshake
+			opts.PoolPerWorker = true
+			tt.configure(&opts)
+
+			report, err := stress.Run(context.Background(), server.URL,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.ErrorsByClass["tls"] != tt.tlsErrors {
+				t.Errorf("Erros TLS incorretos: got %d, want %d", report.ErrorsByClass["tls"], tt.tlsErrors)
+			}
+			if tt.protocol != "" && report.ProtocolDistrib[tt.protocol] != 10 {
+				t.Errorf("Protocolo incorreto: got %v, want %s", report.ProtocolDistrib, tt.protocol)
+			}
+			if tt.tlsVersion != "" {
+				if report.TLSHandshakes != 2 {
+					t.Errorf("Handshakes TLS incorretos: got %d, want 2", report.TLSHandshakes)
+				}
+				versions := 0
+				for suite, count := range report.AttributeDistrib[domain.AttributeTLS] {
+					if strings.HasPrefix(suite, tt.tlsVersion+" ") {
+						versions += count
+					}
+				}
+				if versions != 10 {
+					t.Errorf("Versão TLS incorreta: got %v, want %s", report.AttributeDistrib[domain.AttributeTLS], tt.tlsVersion)
+				}
+			}
+			if tt.tlsCipher != "" && report.AttributeDistrib[domain.AttributeTLS][tt.tlsVersion+" "+tt.tlsCipher] != 10 {
+				t.Errorf("Cipher suite incorreta: got %v, want %s", report.AttributeDistrib[domain.AttributeTLS], tt.tlsCipher)
+			}
+		})
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	trustedCAs, certFile, keyFile := newClientCert(t, "stress-test")
+	_, untrustedCert, untrustedKey := newClientCert(t, "desconhecido")
+
+	server := newTLSServer(t, &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  trustedCAs,
+	})
+
+	tests := []struct {
+		name      string
+		certFile  string
+		keyFile   string
+		successes int
+		tlsErrors int
+	}{
+		{name: "Deve autenticar com o certificado do cliente", certFile: certFile, keyFile: keyFile, successes: 10},
+		{name: "Deve falhar sem certificado do cliente", tlsErrors: 10},
+		{name: "Deve falhar com certificado de CA não confiável", certFile: untrustedCert, keyFile: untrustedKey, tlsErrors: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.TLS.Insecure = true
+			opts.TLS.CertFile = tt.certFile
+			opts.TLS.KeyFile = tt.keyFile
+
+			report, err := stress.Run(context.Background(), server.URL,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.SuccessRequests != tt.successes {
+				t.Errorf("Requests com sucesso incorretas: got %d, want %d", report.SuccessRequests, tt.successes)
+			}
+			if report.ErrorsByClass["tls"] != tt.tlsErrors {
+				t.Errorf("Erros TLS incorretos: got %v, want %d tls", report.ErrorsByClass, tt.tlsErrors)
+			}
+		})
+	}
+}
+
+func TestInvalidTLSOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(opts *httpclient.Options)
+	}{
+		{name: "Versão TLS inválida", configure: func(opts *httpclient.Options) { opts.TLS.MinVersion = "2.0" }},
+		{name: "Cipher suite desconhecida", configure: func(opts *httpclient.Options) { opts.TLS.Ciphers = []string{"TLS_FOO"} }},
+		{name: "Certificado sem chave", configure: func(opts *httpclient.Options) { opts.TLS.CertFile = "client.pem" }},
+		{name: "CA inexistente", configure: func(opts *httpclient.Options) { opts.TLS.CAFile = "/nao/existe.pem" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(1)
+			tt.configure(&opts)
+
+			if _, err := httpclient.NewClient(opts); err == nil {
+				t.Error("Configuração inválida deveria retornar erro")
+			}
+		})
+	}
+}
+
+func TestTLSHandshakeOnSharedPool(t *testing.T) {
+	tests := []struct {
+		name  string
+		http2 bool
+	}{
+		{name: "HTTP/1.1"},
+		{name: "HTTP/2", http2: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// conta as conexoes aceitas; cada uma passa por um unico handshake
+			var conns atomic.Int64
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("ok"))
+			}))
+			server.EnableHTTP2 = true
+			server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					conns.Add(1)
+				}
+			}
+			server.StartTLS()
+			defer server.Close()
+
+			opts := httpclient.DefaultOptions(4)
+			opts.TLS.Insecure = true
+			opts.HTTP2 = tt.http2
+
+			report, err := stress.Run(context.Background(), server.URL,
+				stress.WithRequests(40),
+				stress.WithConcurrency(4),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			// o pool pode entregar a conexao discada por uma request a outra; o handshake deve ser
+			// registrado uma unica vez por quem receber a conexao
+			if report.TLSHandshakes < 1 || int64(report.TLSHandshakes) > conns.Load() {
+				t.Errorf("Handshakes TLS incorretos: got %d, want entre 1 e %d conexões", report.TLSHandshakes, conns.Load())
+			}
+			if report.AvgTLSHandshake <= 0 {
+				t.Errorf("Duração média do handshake não registrada: %v", report.AvgTLSHandshake)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"context"
+	"go-expert-stress-test/infra/httpclient"
+	"go-expert-stress-test/pkg/stress"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConnectionReuse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		options   httpclient.Options
+		minOpened int
+		maxOpened int
+		minReused int
+	}{
+		{
+			name:      "Deve reutilizar conexões com keep-alive",
+			options:   httpclient.DefaultOptions(4),
+			minOpened: 1,
+			maxOpened: 4,
+			minReused: 36,
+		},
+		{
+			name: "Deve abrir uma conexão por request sem keep-alive",
+			options: func() httpclient.Options {
+				opts := httpclient.DefaultOptions(4)
+				opts.DisableKeepAlives = true
+				return opts
+			}(),
+			minOpened: 40,
+			maxOpened: 40,
+			minReused: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := stress.Run(context.Background(), server.URL,
+				stress.WithRequests(40),
+				stress.WithConcurrency(4),
+				stress.WithClientOptions(tt.options),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.ConnsOpened+report.ConnsReused != 40 {
+				t.Errorf("Conexões contabilizadas incorretas: got %d abertas + %d reutilizadas, want 40",
+					report.ConnsOpened, report.ConnsReused)
+			}
+			if report.ConnsOpened < tt.minOpened || report.ConnsOpened > tt.maxOpened {
+				t.Errorf("Conexões abertas incorretas: got %d, want entre %d e %d",
+					report.ConnsOpened, tt.minOpened, tt.maxOpened)
+			}
+			if report.ConnsReused < tt.minReused {
+				t.Errorf("Poucas conexões reutilizadas: got %d, want >= %d", report.ConnsReused, tt.minReused)
+			}
+		})
+	}
+}
+
+func TestPartialClientOptionsPool(t *testing.T) {
+	// as 4 primeiras requests ficam abertas ao mesmo tempo e o pacing deixa as 4 conexoes
+	// ociosas entre as iteracoes; com o limite padrao do net/http apenas 2 ficariam no pool
+	server := httptest.NewServer(firstRequestsBarrier(4, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	report, err := stress.Run(context.Background(), server.URL,
+		stress.WithRequests(20),
+		stress.WithConcurrency(4),
+		stress.WithPacing(20*time.Millisecond),
+		stress.WithClientOptions(httpclient.Options{Timeout: 5 * time.Second}),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if report.ConnsOpened != 4 || report.ConnsReused != 16 {
+		t.Errorf("Opções parciais deveriam manter uma conexão ociosa por worker: got %d abertas e %d reutilizadas, want 4 e 16",
+			report.ConnsOpened, report.ConnsReused)
+	}
+}
+
+func TestNegotiatedProtocol(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		h2c      bool
+		protocol string
+	}{
+		{name: "Deve usar HTTP/1.1 por padrão", protocol: "HTTP/1.1"},
+		{name: "Deve usar HTTP/2 com h2c", h2c: true, protocol: "HTTP/2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.H2C = tt.h2c
+
+			report, err := stress.Run(context.Background(), server.URL,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.ProtocolDistrib[tt.protocol] != 10 {
+				t.Errorf("Protocolo negociado incorreto: got %v, want 10 requests %s", report.ProtocolDistrib, tt.protocol)
+			}
+		})
+	}
+}
+
+func TestHTTP2ALPN(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		http2    bool
+		protocol string
+	}{
+		{name: "Deve usar HTTP/1.1 sem --http2", protocol: "HTTP/1.1"},
+		{name: "Deve negociar HTTP/2 via ALPN com --http2", http2: true, protocol: "HTTP/2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.HTTP2 = tt.http2
+			opts.TLS.Insecure = true
+
+			report, err := stress.Run(context.Background(), server.URL,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.ProtocolDistrib[tt.protocol] != 10 {
+				t.Errorf("Protocolo negociado incorreto: got %v, want 10 requests %s", report.ProtocolDistrib, tt.protocol)
+			}
+		})
+	}
+}
+
+func TestH2CTransportOptions(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("slow") != "" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte(r.Proto))
+	})
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		options    func(*httpclient.Options)
+		wantOK     int
+		wantClass  string
+		wantOpened int
+	}{
+		{
+			name:      "Deve aplicar o timeout de cabeçalhos",
+			path:      "/?slow=1",
+			options:   func(o *httpclient.Options) { o.ResponseHeaderTimeout = 50 * time.Millisecond },
+			wantOK:    0,
+			wantClass: "timeout",
+		},
+		{
+			name:    "Deve aceitar respostas dentro do timeout de cabeçalhos",
+			path:    "/",
+			options: func(o *httpclient.Options) { o.ResponseHeaderTimeout = time.Second },
+			wantOK:  10,
+		},
+		{
+			name:       "Deve abrir uma conexão por request sem keep-alive",
+			path:       "/",
+			options:    func(o *httpclient.Options) { o.DisableKeepAlives = true },
+			wantOK:     10,
+			wantOpened: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(2)
+			opts.H2C = true
+			tt.options(&opts)
+
+			report, err := stress.Run(context.Background(), server.URL+tt.path,
+				stress.WithRequests(10),
+				stress.WithConcurrency(2),
+				stress.WithClientOptions(opts),
+			)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			if report.SuccessRequests != tt.wantOK {
+				t.Errorf("Requests com sucesso incorretas: got %d, want %d", report.SuccessRequests, tt.wantOK)
+			}
+			if tt.wantClass != "" && report.ErrorsByClass[tt.wantClass] != 10-tt.wantOK {
+				t.Errorf("Erros por classe incorretos: got %v, want %d %s", report.ErrorsByClass, 10-tt.wantOK, tt.wantClass)
+			}
+			if tt.wantOpened > 0 && report.ConnsOpened != tt.wantOpened {
+				t.Errorf("Conexões abertas incorretas: got %d, want %d", report.ConnsOpened, tt.wantOpened)
+			}
+		})
+	}
+}
+
+func TestInvalidH2COptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options func(*httpclient.Options)
+	}{
+		{name: "Deve rejeitar proxy", options: func(o *httpclient.Options) { o.Proxy = "http://127.0.0.1:3128" }},
+		{name: "Deve rejeitar --http2", options: func(o *httpclient.Options) { o.HTTP2 = true }},
+		{name: "Deve rejeitar opções de TLS", options: func(o *httpclient.Options) { o.TLS.Insecure = true }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := httpclient.DefaultOptions(1)
+			opts.H2C = true
+			tt.options(&opts)
+
+			if _, err := httpclient.NewClient(opts); err == nil {
+				t.Error("Esperava erro para opção não suportada pelo h2c")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"context"
+	"go-expert-stress-test/pkg/stress"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnixSocketTarget(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "app.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("Unix sockets indisponíveis: %v", err)
+	}
+
+	paths := make(chan string, 10)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		w.Write([]byte("ok"))
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	report, err := stress.Run(context.Background(), "unix://"+socketPath+":/health",
+		stress.WithRequests(10),
+		stress.WithConcurrency(2),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if report.SuccessRequests != 10 {
+		t.Errorf("Requests com sucesso incorretas: got %d, want 10 (erros: %v)", report.SuccessRequests, report.ErrorsByClass)
+	}
+	close(paths)
+	for path := range paths {
+		if path != "/health" {
+			t.Errorf("Path incorreto no servidor: got %s, want /health", path)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	gorilla "github.com/gorilla/websocket"
+	"go-expert-stress-test/infra/websocket"
+	"go-expert-stress-test/pkg/stress"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// servidor de eco que encerra a conexao apos closeAfter mensagens (0 para nunca encerrar)
+func newEchoServer(closeAfter int, received *[][]string, mu *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(echoHandler(closeAfter, received, mu))
+}
+
+// guarda as mensagens recebidas separadas por conexao, na ordem de chegada
+func echoHandler(closeAfter int, received *[][]string, mu *sync.Mutex) http.HandlerFunc {
+	upgrader := gorilla.Upgrader{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		mu.Lock()
+		session := len(*received)
+		*received = append(*received, nil)
+		mu.Unlock()
+
+		for count := 1; ; count++ {
+			kind, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			(*received)[session] = append((*received)[session], string(msg))
+			mu.Unlock()
+			if closeAfter > 0 && count == closeAfter {
+				return
+			}
+			conn.WriteMessage(kind, msg)
+		}
+	}
+}
+
+func TestWebSocketMessages(t *testing.T) {
+	var mu sync.Mutex
+	var received [][]string
+	// a barreira no handshake garante que cada um dos 4 workers abra a sua conexao
+	server := httptest.NewServer(firstRequestsBarrier(4, echoHandler(0, &received, &mu)))
+	defer server.Close()
+
+	opts := websocket.DefaultOptions()
+	opts.Messages = []string{"join", "hello", "leave"}
+	client, err := websocket.NewClient(opts)
+	if err != nil {
+		t.Fatalf("Erro ao criar cliente: %v", err)
+	}
+
+	report, err := stress.Run(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"),
+		stress.WithRequests(24),
+		stress.WithConcurrency(4),
+		stress.WithExecutor(client),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if report.StatusDistrib[200] != 24 {
+		t.Errorf("Mensagens respondidas incorretas: got %v, want 24", report.StatusDistrib)
+	}
+	if report.ConnsOpened != 4 {
+		t.Errorf("Cada worker deveria abrir uma conexão: got %d, want 4", report.ConnsOpened)
+	}
+	if report.ProtocolDistrib["websocket"] != 24 {
+		t.Errorf("Protocolo incorreto: got %v", report.ProtocolDistrib)
+	}
+	if report.AvgConnectTime <= 0 || report.Throughput <= 0 {
+		t.Errorf("Tempo de abertura e mensagens/s deveriam ser registrados: %v, %.1f", report.AvgConnectTime, report.Throughput)
+	}
+
+	// os workers dividem as requisicoes de uma fila compartilhada, entao apenas o total e fixo; em
+	// cada conexao o roteiro deve ser enviado em ciclo
+	total := 0
+	for _, session := range received {
+		total += len(session)
+		for i, msg := range session {
+			if msg != opts.Messages[i%len(opts.Messages)] {
+				t.Errorf("Roteiro não foi enviado em ciclo na conexão: %v", session)
+				break
+			}
+		}
+	}
+	if len(received) != 4 || total != 24 {
+		t.Errorf("Mensagens recebidas incorretas: got %d em %d conexões, want 24 em 4", total, len(received))
+	}
+}
+
+func TestWebSocketDisconnects(t *testing.T) {
+	var mu sync.Mutex
+	var received [][]string
+	// o servidor encerra a conexao na terceira mensagem
+	server := newEchoServer(3, &received, &mu)
+	defer server.Close()
+
+	report, err := stress.Run(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"),
+		stress.WithRequests(6),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if report.Disconnects != 2 {
+		t.Errorf("Desconexões incorretas: got %d, want 2", report.Disconnects)
+	}
+	if report.ErrorsByClass["connection"] != 2 || report.StatusDistrib[200] != 4 {
+		t.Errorf("Resultado incorreto: erros %v, status %v", report.ErrorsByClass, report.StatusDistrib)
+	}
+	if report.ConnsOpened != 2 {
+		t.Errorf("A conexão deveria ser reaberta após a desconexão: got %d aberturas, want 2", report.ConnsOpened)
+	}
+}
+
+func TestWebSocketNormalClose(t *testing.T) {
+	var closes atomic.Int64
+	upgrader := gorilla.Upgrader{}
+	// servidor de eco que conta os frames de fechamento normal recebidos
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			kind, msg, err := conn.ReadMessage()
+			if err != nil {
+				if gorilla.IsCloseError(err, gorilla.CloseNormalClosure) {
+					closes.Add(1)
+				}
+				return
+			}
+			conn.WriteMessage(kind, msg)
+		}
+	}))
+	defer server.Close()
+
+	opts := websocket.DefaultOptions()
+	opts.Messages = []string{"ping"}
+
+	report, err := stress.Run(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"),
+		stress.WithRequests(12),
+		stress.WithConcurrency(4),
+		stress.WithWebSocketOptions(opts),
+	)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if report.StatusDistrib[200] != 12 {
+		t.Errorf("Mensagens respondidas incorretas: got %v, want 12", report.StatusDistrib)
+	}
+
+	// o servidor le o frame de fechamento de forma assincrona
+	deadline := time.Now().Add(2 * time.Second)
+	for closes.Load() < int64(report.ConnsOpened) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if report.ConnsOpened != 4 || closes.Load() != 4 {
+		t.Errorf("Cada sessão deveria ser encerrada com fechamento normal: got %d fechamentos para %d conexões, want 4", closes.Load(), report.ConnsOpened)
+	}
+}
+
+func TestWebSocketCancel(t *testing.T) {
+	upgrader := gorilla.Upgrader{}
+	// servidor que le as mensagens e nunca responde
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	// sem timeout, apenas o cancelamento do contexto encerra a espera pela resposta
+	opts := websocket.DefaultOptions()
+	opts.Timeout = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := stress.Run(ctx, "ws"+strings.TrimPrefix(server.URL, "http"),
+		stress.WithRequests(4),
+		stress.WithConcurrency(2),
+		stress.WithWebSocketOptions(opts),
+	)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Erro incorreto: got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("O cancelamento deveria interromper a leitura: teste levou %v", elapsed)
+	}
+}
@@ -1,60 +1,161 @@
 package usecases
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"go-expert-stress-test/domain"
-	"go-expert-stress-test/infra/progress"
+	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type LoadTesterUseCase struct {
-	httpClient domain.HTTPClient
-	reporter   domain.Reporter
+	executor domain.Executor
+	reporter domain.Reporter
+	observer domain.ProgressObserver
 }
 
-// retorna instancia do LoadTesterUseCase
-func NewLoadTesterUseCase(client domain.HTTPClient, reporter domain.Reporter) *LoadTesterUseCase {
+// resultado acompanhado do worker que o produziu; finished indica que o worker terminou e
+// nao envia mais resultados
+type workerResult struct {
+	workerID int
+	result   domain.TestResult
+	finished bool
+}
+
+// retorna instancia do LoadTesterUseCase; o observer recebe os eventos do teste e pode ser nil
+func NewLoadTesterUseCase(executor domain.Executor, reporter domain.Reporter, observer domain.ProgressObserver) *LoadTesterUseCase {
 	return &LoadTesterUseCase{
-		httpClient: client,
-		reporter:   reporter,
+		executor: executor,
+		reporter: reporter,
+		observer: observer,
+	}
+}
+
+// notifica o observer, quando existir
+func (lt *LoadTesterUseCase) emit(event domain.Event) {
+	if lt.observer == nil {
+		return
+	}
+	event.Time = time.Now()
+	lt.observer.OnEvent(event)
+}
+
+// executa uma requisicao repetindo as tentativas que falharem segundo a politica; a duracao
+// final inclui as tentativas anteriores e as esperas, que tambem ficam em RetryLatency. Cada
+// nova tentativa tambem aguarda o limitador, para que os retries nao ultrapassem a taxa
+func (lt *LoadTesterUseCase) execute(ctx context.Context, executor domain.Executor, req domain.Request, policy domain.RetryPolicy, limiter *rateLimiter) *domain.TestResult {
+	start := time.Now()
+	result := lt.attempt(ctx, executor, req)
+	for retry := 1; retry <= policy.MaxRetries && policy.Retryable(result); retry++ {
+		if !sleep(ctx, backoff(policy, retry)) || !limiter.wait(ctx) {
+			break
+		}
+		next := lt.attempt(ctx, executor, req)
+		// uma tentativa interrompida pelo cancelamento nao substitui o resultado anterior
+		if ctx.Err() != nil && next.Error != nil {
+			break
+		}
+		result = next
+		result.Retries = retry
+	}
+	if result.Retries > 0 {
+		total := time.Since(start)
+		result.RetryLatency = total - result.Duration
+		result.Duration = total
+	}
+	return result
+}
+
+// executa uma unica tentativa; quando o executor falha o erro e registrado no resultado, para
+// que a requisicao apareca no relatorio como erro em vez de ser descartada
+func (lt *LoadTesterUseCase) attempt(ctx context.Context, executor domain.Executor, req domain.Request) *domain.TestResult {
+	result, err := executor.Execute(ctx, req)
+	if result == nil {
+		if err == nil {
+			err = errors.New("executor returned no result")
+		}
+		result = &domain.TestResult{}
+	}
+	if err != nil && result.Error == nil {
+		result.Error = err
 	}
+	return result
 }
 
 func (lt *LoadTesterUseCase) Execute(config domain.TestConfig) (*domain.TestReport, error) {
+	return lt.ExecuteContext(context.Background(), config)
+}
+
+// executa o teste ate o fim ou ate o contexto ser cancelado; no cancelamento o relatorio
+// contem apenas as requisicoes concluidas e o erro do contexto e retornado
+func (lt *LoadTesterUseCase) ExecuteContext(ctx context.Context, config domain.TestConfig) (*domain.TestReport, error) {
 	results := make([]domain.TestResult, 0, config.Requests)
-	resultsChan := make(chan domain.TestResult, config.Requests)
+	resultsChan := make(chan workerResult, config.Requests)
 	var wg sync.WaitGroup
 
 	startTime := time.Now()
 
-	// cria uma instancia do ProgressTracker com as configuesções
-	progressTracker := progress.NewProgressTracker(config.Concurrency, config.Requests)
-	progressTracker.Start()
-	defer progressTracker.Stop()
+	lt.emit(domain.Event{Type: domain.EventStarted, Config: &config})
+	lt.emit(domain.Event{Type: domain.EventStageChanged, Stage: domain.StageRunning})
 
 	// canal para controlar a conclusao das chamadas
 	done := make(chan struct{})
 
-	// distribui as requisicoes entre os workers
-	requestsPerWorker := config.Requests / config.Concurrency
-	remainder := config.Requests % config.Concurrency
+	// quando existe limite de taxa, cada requisicao consome um tick compartilhado entre os workers
+	limiter := newRateLimiter(config.Rate)
+	defer limiter.stop()
+
+	// fila compartilhada: cada worker retira uma ficha antes de cada requisicao, de forma que os
+	// workers mais rapidos enviam mais requisicoes e o total enviado e sempre config.Requests
+	var pending atomic.Int64
+	pending.Store(int64(config.Requests))
+
+	// nao faz sentido iniciar mais workers do que requisicoes
+	workers := min(config.Concurrency, config.Requests)
 
 	// eu amo isso de mais
-	for workerID := 0; workerID < config.Concurrency; workerID++ {
+	for workerID := 0; workerID < workers; workerID++ {
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
 
-			requests := requestsPerWorker
-			if id == config.Concurrency-1 {
-				requests += remainder
+			lt.emit(domain.Event{Type: domain.EventWorkerStarted, WorkerID: id})
+			// o fim do worker passa pelo mesmo canal dos resultados, para que o evento so seja
+			// emitido depois de todos os request_done do worker
+			defer func() { resultsChan <- workerResult{workerID: id, finished: true} }()
+
+			// cada worker se comporta como um usuario independente quando o cliente suporta sessoes
+			executor := lt.executor
+			if sessions, ok := executor.(domain.SessionFactory); ok {
+				executor = sessions.NewSession()
+				// sessoes que mantem recursos proprios (ex: conexoes) sao encerradas junto com o worker
+				if closer, ok := executor.(io.Closer); ok {
+					defer closer.Close()
+				}
 			}
 
-			for i := 0; i < requests; i++ {
-				result, _ := lt.httpClient.Get(config.URL)
-				resultsChan <- *result
-				progressTracker.IncrementWorker(id)
+			// think time e pacing acontecem entre as iteracoes e nao entram na duracao das requisicoes
+			var next time.Time
+			for i := 0; pending.Load() > 0; i++ {
+				if i > 0 && !sleep(ctx, time.Until(next)) {
+					return
+				}
+				if pending.Add(-1) < 0 {
+					return
+				}
+				if !limiter.wait(ctx) {
+					return
+				}
+				start := time.Now()
+				result := lt.execute(ctx, executor, domain.Request{Target: config.URL, WorkerID: id}, config.Retry, limiter)
+				// requisicoes interrompidas pelo cancelamento do teste nao entram no relatorio
+				if ctx.Err() != nil && result.Error != nil {
+					return
+				}
+				resultsChan <- workerResult{workerID: id, result: *result}
+				next = config.NextIteration(start, time.Now())
 			}
 		}(workerID)
 	}
@@ -67,19 +168,24 @@ func (lt *LoadTesterUseCase) Execute(config domain.TestConfig) (*domain.TestRepo
 	}()
 
 	// itera entre os resultados que vieram do canal
-	for result := range resultsChan {
-		results = append(results, result)
+	for wr := range resultsChan {
+		if wr.finished {
+			lt.emit(domain.Event{Type: domain.EventWorkerFinished, WorkerID: wr.workerID})
+			continue
+		}
+		results = append(results, wr.result)
+		lt.emit(domain.Event{Type: domain.EventRequestDone, WorkerID: wr.workerID, Result: &wr.result})
 	}
 
 	// espera a conclusao de tudo
 	<-done
 
-	// faz uma pausa para garantir que todas as barra de progresso concluiram o trabalho
-	time.Sleep(500 * time.Millisecond)
-
-	// limpa a tela para imprimir o resultado
-	fmt.Print("\033[H\033[2J")
+	lt.emit(domain.Event{Type: domain.EventStageChanged, Stage: domain.StageReporting})
 
 	// chama o reporter para gerar o resultado do relatorio
-	return lt.reporter.GenerateReport(results, time.Since(startTime)), nil
+	report := lt.reporter.GenerateReport(results, time.Since(startTime))
+
+	lt.emit(domain.Event{Type: domain.EventFinished, Report: report})
+
+	return report, ctx.Err()
 }
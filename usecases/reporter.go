@@ -1,7 +1,9 @@
 package usecases
 
 import (
+	"fmt"
 	"go-expert-stress-test/domain"
+	"slices"
 	"time"
 )
 
@@ -14,29 +16,154 @@ func NewReporter() *Reporter {
 // gera um report para o resultado do teste com a duração e o status
 func (r *Reporter) GenerateReport(results []domain.TestResult, totalDuration time.Duration) *domain.TestReport {
 	report := &domain.TestReport{
-		TotalDuration: totalDuration,
-		TotalRequests: len(results),
-		StatusDistrib: make(map[int]int),
+		TotalDuration:    totalDuration,
+		TotalRequests:    len(results),
+		StatusDistrib:    make(map[int]int),
+		ErrorsByClass:    make(map[string]int),
+		ProtocolDistrib:  make(map[string]int),
+		IPStats:          make(map[string]domain.IPStats),
+		RedirectChains:   make(map[string]int),
+		AttributeDistrib: make(map[string]map[string]int),
+		GraphQLErrors:    make(map[string]int),
 	}
+	ipDurations := make(map[string]time.Duration)
+	durations := make([]time.Duration, 0, len(results))
 
-	var totalReqDuration time.Duration
+	var totalReqDuration, totalHandshake, totalConnect time.Duration
+	var connects, firstEvents int
+	var totalFirstEvent, totalGap, totalHeld, totalRetryLatency time.Duration
 	for _, result := range results {
 		totalReqDuration += result.Duration
+		durations = append(durations, result.Duration)
+
+		if result.ConnectDuration > 0 {
+			connects++
+			totalConnect += result.ConnectDuration
+		}
+		if result.Disconnected {
+			report.Disconnects++
+		}
+
+		if result.TLSHandshake > 0 {
+			report.TLSHandshakes++
+			totalHandshake += result.TLSHandshake
+		}
+
+		if result.RemoteIP != "" {
+			stats := report.IPStats[result.RemoteIP]
+			stats.Requests++
+			if result.Error != nil {
+				stats.Errors++
+			}
+			report.IPStats[result.RemoteIP] = stats
+			ipDurations[result.RemoteIP] += result.Duration
+		}
+
+		if result.ConnOpened {
+			report.ConnsOpened++
+		}
+		if result.ConnReused {
+			report.ConnsReused++
+		}
+
+		// o status e os atributos recebidos entram nas distribuicoes mesmo quando a requisicao
+		// conta como erro, ex: DeadlineExceeded do gRPC (504) ou um corpo que nao pode ser lido;
+		// erros sem resposta nao tem status
+		if result.Error == nil || result.Status != 0 {
+			report.StatusDistrib[result.Status]++
+		}
+		for key, value := range result.Attributes {
+			if report.AttributeDistrib[key] == nil {
+				report.AttributeDistrib[key] = make(map[string]int)
+			}
+			report.AttributeDistrib[key][value]++
+		}
 
 		if result.Error != nil {
 			report.ErrorCount++
+			report.ErrorsByClass[domain.ErrorClass(result.Error)]++
+			for _, message := range result.GraphQLErrors {
+				report.GraphQLErrors[message]++
+			}
 			continue
 		}
 
-		report.StatusDistrib[result.Status]++
-		if result.Status == 200 {
+		if stream := result.Stream; stream != nil {
+			report.Streams++
+			report.Events += stream.Events
+			if stream.Events > 0 {
+				firstEvents++
+				totalFirstEvent += stream.FirstEvent
+			}
+			totalGap += stream.TotalGap
+			totalHeld += stream.Held
+			report.MaxEventGap = max(report.MaxEventGap, stream.MaxGap)
+		}
+
+		report.WireBytes += result.WireBytes
+		report.DecodedBytes += result.DecodedBytes
+		if result.Redirects > 0 {
+			report.Redirected++
+			report.RedirectHops += result.Redirects
+			report.RedirectChains[fmt.Sprintf("%d -> %d", result.InitialStatus, result.Status)]++
+		}
+		if result.Protocol != "" {
+			report.ProtocolDistrib[result.Protocol]++
+		}
+		if result.Succeeded() {
 			report.SuccessRequests++
 		}
+
+		// uma requisicao repetida falhou na primeira tentativa
+		if result.Retries > 0 {
+			report.Retried++
+			report.Retries += result.Retries
+			totalRetryLatency += result.RetryLatency
+			if result.Succeeded() {
+				report.RetriesRecovered++
+			}
+		} else if result.Succeeded() {
+			report.FirstAttemptOK++
+		}
 	}
 
 	if report.TotalRequests > 0 {
 		report.AverageDuration = totalReqDuration / time.Duration(report.TotalRequests)
 	}
+	slices.Sort(durations)
+	report.P50 = domain.Percentile(durations, 50)
+	report.P95 = domain.Percentile(durations, 95)
+	report.P99 = domain.Percentile(durations, 99)
+	for ip, stats := range report.IPStats {
+		stats.AverageDuration = ipDurations[ip] / time.Duration(stats.Requests)
+		report.IPStats[ip] = stats
+	}
+	if totalDuration > 0 {
+		report.Throughput = float64(report.TotalRequests) / totalDuration.Seconds()
+		report.EventsPerSecond = float64(report.Events) / totalDuration.Seconds()
+	}
+	if report.Streams > 0 {
+		report.AvgStreamHeld = totalHeld / time.Duration(report.Streams)
+	}
+	if firstEvents > 0 {
+		report.AvgFirstEvent = totalFirstEvent / time.Duration(firstEvents)
+	}
+	// cada stream com n eventos tem n-1 intervalos
+	if gaps := report.Events - firstEvents; gaps > 0 {
+		report.AvgEventGap = totalGap / time.Duration(gaps)
+	}
+	if report.Retried > 0 {
+		report.AvgRetryLatency = totalRetryLatency / time.Duration(report.Retried)
+	}
+	if connects > 0 {
+		report.AvgConnectTime = totalConnect / time.Duration(connects)
+	}
+	if report.WireBytes > 0 {
+		report.CompressionRatio = float64(report.DecodedBytes) / float64(report.WireBytes)
+	}
+	if report.TLSHandshakes > 0 {
+		report.AvgTLSHandshake = totalHandshake / time.Duration(report.TLSHandshakes)
+	}
 
 	return report
 }
@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"context"
+	"go-expert-stress-test/domain"
+	"math/rand/v2"
+	"time"
+)
+
+// espera antes da tentativa retry (1 para a primeira nova tentativa): backoff exponencial a
+// partir de BaseDelay, limitado por MaxDelay, com metade do intervalo sorteada para evitar que
+// os workers repitam as requisicoes ao mesmo tempo
+func backoff(policy domain.RetryPolicy, retry int) time.Duration {
+	// sem MaxDelay a espera e limitada a uma hora para evitar overflow
+	limit := policy.MaxDelay
+	if limit <= 0 {
+		limit = time.Hour
+	}
+	delay := policy.BaseDelay
+	for i := 1; i < retry && delay < limit; i++ {
+		delay *= 2
+	}
+	delay = min(delay, limit)
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// aguarda d ou o cancelamento do contexto; retorna false quando o contexto foi cancelado
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"go-expert-stress-test/domain"
+	"time"
+)
+
+// busca o ponto de saturacao do alvo aumentando a concorrencia ou a taxa degrau a degrau
+type ThroughputSearchUseCase struct {
+	executor domain.Executor
+	reporter domain.Reporter
+	observer domain.ProgressObserver
+}
+
+// retorna instancia do ThroughputSearchUseCase; o observer recebe os eventos de cada degrau e
+// um step_done ao fim de cada um, e pode ser nil
+func NewThroughputSearchUseCase(executor domain.Executor, reporter domain.Reporter, observer domain.ProgressObserver) *ThroughputSearchUseCase {
+	return &ThroughputSearchUseCase{
+		executor: executor,
+		reporter: reporter,
+		observer: observer,
+	}
+}
+
+// executa um teste com config.Requests requisicoes por degrau ate um limite ser ultrapassado ou
+// search.Max ser atingido; no modo rate a concorrencia de cada degrau acompanha a taxa. No
+// cancelamento retorna os degraus concluidos e o erro do contexto
+func (s *ThroughputSearchUseCase) Search(ctx context.Context, config domain.TestConfig, search domain.SearchConfig) (*domain.SearchReport, error) {
+	result := &domain.SearchReport{Config: search}
+	loadTester := NewLoadTesterUseCase(s.executor, s.reporter, s.observer)
+
+	for level := search.Start; level <= search.Max; level += search.Step {
+		stepConfig := config
+		if search.Mode == domain.SearchRate {
+			// um worker por requisicao/s sustenta a taxa enquanto a latencia ficar abaixo de 1s;
+			// com menos workers a vazao seria limitada pela concorrencia e nao pelo alvo
+			stepConfig.Rate = level
+			stepConfig.Concurrency = max(config.Concurrency, level)
+		} else {
+			stepConfig.Concurrency = level
+		}
+
+		report, err := loadTester.ExecuteContext(ctx, stepConfig)
+		if err != nil {
+			return result, err
+		}
+
+		step := evaluateStep(level, report, search)
+		result.Steps = append(result.Steps, step)
+		if s.observer != nil {
+			s.observer.OnEvent(domain.Event{Type: domain.EventStepDone, Time: time.Now(), Step: &step})
+		}
+
+		if step.Breach != "" {
+			break
+		}
+		sustainable := step
+		result.Sustainable = &sustainable
+	}
+
+	return result, nil
+}
+
+// resume o relatorio de um degrau e verifica os limites da busca
+func evaluateStep(level int, report *domain.TestReport, search domain.SearchConfig) domain.SearchStep {
+	step := domain.SearchStep{
+		Level:      level,
+		Throughput: report.Throughput,
+		P99:        report.P99,
+		Report:     report,
+	}
+	// sem sucesso sao os erros e as respostas fora de 2xx, como no dashboard
+	if report.TotalRequests > 0 {
+		step.ErrorRate = float64(report.TotalRequests-report.SuccessRequests) / float64(report.TotalRequests) * 100
+	}
+
+	switch {
+	case step.ErrorRate > search.MaxErrorRate:
+		step.Breach = domain.BreachErrorRate
+	case search.MaxP99 > 0 && step.P99 > search.MaxP99:
+		step.Breach = domain.BreachP99
+	}
+	return step
+}
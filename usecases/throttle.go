@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+	"time"
+)
+
+// limita as requisicoes de todos os workers a uma taxa por segundo; o limiter nil nao limita
+type rateLimiter struct {
+	ticker *time.Ticker
+}
+
+// retorna nil quando rate e zero, ou seja, sem limite
+func newRateLimiter(rate int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &rateLimiter{ticker: time.NewTicker(time.Second / time.Duration(rate))}
+}
+
+// aguarda o proximo tick compartilhado; retorna false quando o contexto foi cancelado
+func (r *rateLimiter) wait(ctx context.Context) bool {
+	if r == nil {
+		return ctx.Err() == nil
+	}
+	select {
+	case <-r.ticker.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (r *rateLimiter) stop() {
+	if r != nil {
+		r.ticker.Stop()
+	}
+}